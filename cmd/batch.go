@@ -77,7 +77,7 @@ Words should be provided as a space-separated list.`,
 			}
 
 			// Save the output to a file
-			filename := fmt.Sprintf("%s%s.%s", outputPrefix, result.Word, batchFormat)
+			filename := outputPrefix + result.Word + outputExtension(batchFormat)
 			err = os.WriteFile(filename, []byte(output), 0644)
 			if err != nil {
 				logger.Error("Failed to save output",
@@ -101,7 +101,7 @@ Words should be provided as a space-separated list.`,
 		fmt.Printf("- Failed: %d\n", failureCount)
 
 		if failureCount > 0 {
-			os.Exit(1)
+			exit(1)
 		}
 	},
 }
@@ -109,7 +109,7 @@ Words should be provided as a space-separated list.`,
 func init() {
 	rootCmd.AddCommand(batchCmd)
 
-	batchCmd.Flags().StringVarP(&batchFormat, "output", "o", "json", "Output format (text, json)")
+	addFormatFlag(batchCmd, &batchFormat, "output", "o", "json")
 	batchCmd.Flags().IntVarP(&workers, "workers", "w", 5, "Number of concurrent workers")
 	batchCmd.Flags().IntVarP(&timeoutSecs, "timeout", "t", 30, "Timeout in seconds per word")
 	batchCmd.Flags().StringVarP(&outputPrefix, "prefix", "p", "", "Output filename prefix")
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/amirhossein-jamali/goden-crawler/internal/application/services"
+	"github.com/amirhossein-jamali/goden-crawler/internal/export"
 	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
 	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
@@ -26,6 +27,9 @@ var (
 	bulkWorkers    int
 	bulkTimeoutSec int
 	bulkFormat     string
+	bulkJSONL      string
+	bulkParquet    string
+	bulkDistribute bool
 )
 
 // bulkCmd represents the bulk command
@@ -34,37 +38,52 @@ var bulkCmd = &cobra.Command{
 	Short: "Process a large number of words from a file",
 	Long: `Process a large number of German words from a file.
 Each word should be on a separate line in the input file.
-Results will be saved to the specified output directory.`,
+Results will be saved to the specified output directory, one file per word.
+With --jsonl and/or --parquet all results are written to a single JSON Lines
+stream or Parquet file instead (see "export jsonl" and "export parquet").
+With --distributed the words are queued for "worker" processes instead.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Validate input file
 		if bulkInputFile == "" {
 			fmt.Println("Error: Input file is required")
 			cmd.Help()
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Check if input file exists
 		if _, err := os.Stat(bulkInputFile); os.IsNotExist(err) {
 			fmt.Printf("Error: Input file '%s' not found\n", bulkInputFile)
-			os.Exit(1)
+			exit(1)
 		}
 
-		// Create output directory if it doesn't exist
-		if err := os.MkdirAll(bulkOutputDir, 0755); err != nil {
-			fmt.Printf("Error creating output directory: %v\n", err)
-			os.Exit(1)
+		if bulkDistribute {
+			enqueueBulkJob()
+			return
+		}
+
+		// Open the single-file exports, or create the output directory for per-word files
+		stream, files, err := openBulkStreams()
+		if err != nil {
+			fmt.Printf("Error creating export file: %v\n", err)
+			exit(1)
+		}
+		if stream == nil {
+			if err := os.MkdirAll(bulkOutputDir, 0755); err != nil {
+				fmt.Printf("Error creating output directory: %v\n", err)
+				exit(1)
+			}
 		}
 
 		// Read words from file
 		words, err := readWordsFromFile(bulkInputFile)
 		if err != nil {
 			fmt.Printf("Error reading input file: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if len(words) == 0 {
 			fmt.Println("No words found in the input file")
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Printf("Found %d words in the input file\n", len(words))
@@ -74,6 +93,7 @@ Results will be saved to the specified output directory.`,
 		// Get services from container
 		wordService := container.GetWordService()
 		wordRepository := container.GetWordRepository()
+		container.WatchInvalidations(context.Background())
 
 		// Process words in batches
 		totalSuccess := 0
@@ -89,7 +109,7 @@ Results will be saved to the specified output directory.`,
 			fmt.Printf("\nProcessing batch %d/%d (%d words)...\n",
 				(i/bulkBatchSize)+1, (len(words)+bulkBatchSize-1)/bulkBatchSize, len(batchWords))
 
-			success, failure := processBatch(batchWords, wordService, wordRepository)
+			success, failure := processBatch(batchWords, wordService, wordRepository, stream)
 			totalSuccess += success
 			totalFailure += failure
 
@@ -100,19 +120,55 @@ Results will be saved to the specified output directory.`,
 			}
 		}
 
+		if stream != nil {
+			if err := stream.Close(); err != nil {
+				fmt.Printf("Error finishing export file: %v\n", err)
+				exit(1)
+			}
+			for _, file := range files {
+				file.Close()
+			}
+		}
+
 		// Print summary
 		fmt.Printf("\nBulk processing complete:\n")
 		fmt.Printf("- Total words: %d\n", len(words))
 		fmt.Printf("- Successful: %d\n", totalSuccess)
 		fmt.Printf("- Failed: %d\n", totalFailure)
-		fmt.Printf("Results saved to: %s\n", bulkOutputDir)
+		if stream == nil {
+			fmt.Printf("Results saved to: %s\n", bulkOutputDir)
+		}
+		for _, file := range files {
+			fmt.Printf("Results saved to: %s\n", file.Name())
+		}
 
 		if totalFailure > 0 {
-			os.Exit(1)
+			exit(1)
 		}
 	},
 }
 
+// enqueueBulkJob queues the words of the input file for distributed workers
+func enqueueBulkJob() {
+	words, err := readWordsFromFile(bulkInputFile)
+	if err != nil {
+		fmt.Printf("Error reading input file: %v\n", err)
+		exit(1)
+	}
+	if len(words) == 0 {
+		fmt.Println("No words found in the input file")
+		exit(1)
+	}
+
+	job, err := newWorkQueue().Enqueue(words)
+	if err != nil {
+		fmt.Println("🚨 Error queuing words:", err)
+		exit(1)
+	}
+	fmt.Printf("Queued %d words as job %s.\n", len(words), job)
+	fmt.Println("Start workers with \"goden-crawler worker\" and follow the progress with \"goden-crawler worker status\".")
+}
+
 // readWordsFromFile reads words from a file, one word per line
 func readWordsFromFile(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
@@ -137,8 +193,53 @@ func readWordsFromFile(filePath string) ([]string, error) {
 	return words, nil
 }
 
-// processBatch processes a batch of words
-func processBatch(words []string, wordService *services.WordService, wordRepository *repository.WordRepository) (int, int) {
+// openBulkStreams creates the files given with --jsonl and --parquet and returns
+// a writer for all of them, or nil when results go to per-word files
+func openBulkStreams() (export.Writer, []*os.File, error) {
+	var writers []export.Writer
+	var files []*os.File
+	fail := func(err error) (export.Writer, []*os.File, error) {
+		for _, file := range files {
+			file.Close()
+		}
+		return nil, nil, err
+	}
+
+	for _, target := range []struct {
+		path      string
+		newWriter func(file *os.File) (export.Writer, error)
+	}{
+		{bulkJSONL, func(file *os.File) (export.Writer, error) {
+			return export.NewJSONLWriter(file, export.CompressionFromPath(file.Name()))
+		}},
+		{bulkParquet, func(file *os.File) (export.Writer, error) {
+			return export.NewParquetWriter(file, "")
+		}},
+	} {
+		if target.path == "" {
+			continue
+		}
+		file, err := os.Create(target.path)
+		if err != nil {
+			return fail(err)
+		}
+		files = append(files, file)
+		writer, err := target.newWriter(file)
+		if err != nil {
+			return fail(err)
+		}
+		writers = append(writers, writer)
+	}
+
+	if len(writers) == 0 {
+		return nil, nil, nil
+	}
+	return export.MultiWriter(writers...), files, nil
+}
+
+// processBatch processes a batch of words. Results are written to stream if it
+// is set, and otherwise to one file per word in the output directory.
+func processBatch(words []string, wordService *services.WordService, wordRepository *repository.WordRepository, stream export.Writer) (int, int) {
 	// Create a batch service
 	batchService := services.NewBatchService(
 		wordService,
@@ -164,8 +265,21 @@ func processBatch(words []string, wordService *services.WordService, wordReposit
 			continue
 		}
 
+		if stream != nil {
+			if err := stream.Write(result.Data); err != nil {
+				logger.Error("Failed to export word",
+					logger.F("word", result.Word),
+					logger.F("error", err))
+				failureCount++
+				continue
+			}
+			logger.Info("Successfully processed word", logger.F("word", result.Word))
+			successCount++
+			continue
+		}
+
 		// Save the output to a file
-		filename := filepath.Join(bulkOutputDir, fmt.Sprintf("%s.%s", result.Word, bulkFormat))
+		filename := filepath.Join(bulkOutputDir, result.Word+outputExtension(bulkFormat))
 
 		// Format the output
 		output, err := formatter.FormatOutput(result.Data, bulkFormat)
@@ -206,7 +320,10 @@ func init() {
 	bulkCmd.Flags().IntVarP(&bulkBatchSize, "batch-size", "b", 10, "Number of words to process in each batch")
 	bulkCmd.Flags().IntVarP(&bulkWorkers, "workers", "w", 5, "Number of concurrent workers")
 	bulkCmd.Flags().IntVarP(&bulkTimeoutSec, "timeout", "t", 30, "Timeout in seconds per word")
-	bulkCmd.Flags().StringVarP(&bulkFormat, "format", "f", "json", "Output format (text, json)")
+	addFormatFlag(bulkCmd, &bulkFormat, "format", "f", "json")
+	bulkCmd.Flags().StringVar(&bulkJSONL, "jsonl", "", "Write all results to this JSON Lines file (.gz or .zst compresses)")
+	bulkCmd.Flags().StringVar(&bulkParquet, "parquet", "", "Write all results to this Parquet file")
+	bulkCmd.Flags().BoolVar(&bulkDistribute, "distributed", false, "Queue the words for \"worker\" processes instead of crawling them here")
 
 	// Mark required flags
 	bulkCmd.MarkFlagRequired("input")
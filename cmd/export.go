@@ -0,0 +1,604 @@
+// File: cmd/export.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/export"
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/internal/media"
+	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ankiOutput string
+
+	ankiDeckName  string
+	ankiNoteType  string
+	ankiFrontFile string
+	ankiBackFile  string
+	ankiCSSFile   string
+
+	tableFormat  string
+	tableOutput  string
+	tableFromDir string
+
+	lexiconOutput  string
+	lexiconTitle   string
+	lexiconFromDir string
+	ontolexSyntax  string
+	ontolexBase    string
+
+	dictOutput      string
+	dictName        string
+	dictDescription string
+	dictFormat      string
+	dictCompress    bool
+	dictdFormat     bool
+	dictFromDir     string
+
+	jsonlOutput        string
+	parquetOutput      string
+	jsonlCompression   string
+	parquetCompression string
+	streamFromDir      string
+
+	exportBackend       string
+	exportFormat        string
+	exportOutput        string
+	exportWordTypes     []string
+	exportFrequencies   []string
+	exportFetchedAfter  string
+	exportFetchedBefore string
+)
+
+// streamFormats are the formats written by the export package rather than the formatter registry
+var streamFormats = map[string]string{
+	"jsonl":   "JSON Lines with schema version (gzip/zstd by extension)",
+	"parquet": "Apache Parquet with a flattened schema",
+}
+
+// exportCmd writes all words of a backend, and groups commands for other export formats
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export stored words to other formats",
+	Long: `Without a subcommand, streams every word stored in one backend (--from)
+through any output format to a file or stdout. Words are read with the
+backend's cursor, so repositories of any size are exported without loading
+them into memory.
+
+Words can be filtered by word type (matched as part of the label, so "verb"
+matches "schwaches Verb"), Duden frequency and fetch date. Dates are given as
+2006-01-02 or RFC 3339.
+
+The default format jsonl writes dumps that "import" loads back; json writes
+one array and tabular formats a single table. Other formats render each word
+in turn.
+
+Examples:
+  goden-crawler export -o words.jsonl.gz
+  goden-crawler export --from postgres --word-type verb -f csv -o verbs.csv
+  goden-crawler export --fetched-before 2025-01-01 -f text`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := repository.WordFilter{
+			WordTypes:   exportWordTypes,
+			Frequencies: exportFrequencies,
+		}
+		for _, date := range []struct {
+			flag, value string
+			target      *time.Time
+		}{
+			{"--fetched-after", exportFetchedAfter, &filter.FetchedAfter},
+			{"--fetched-before", exportFetchedBefore, &filter.FetchedBefore},
+		} {
+			if date.value == "" {
+				continue
+			}
+			parsed, err := parseExportDate(date.value)
+			if err != nil {
+				fmt.Printf("🚨 Error: invalid %s date %q (use 2006-01-02 or RFC 3339)\n", date.flag, date.value)
+				exit(1)
+			}
+			*date.target = parsed
+		}
+
+		// Progress goes to stderr when words are written to stdout
+		status := os.Stdout
+		out := os.Stdout
+		if exportOutput != "-" {
+			file, err := os.Create(exportOutput)
+			if err != nil {
+				fmt.Println("🚨 Error creating output file:", err)
+				exit(1)
+			}
+			defer file.Close()
+			out = file
+		} else {
+			status = os.Stderr
+		}
+
+		var writer export.Writer
+		var err error
+		switch exportFormat {
+		case "jsonl":
+			compression := export.CompressionNone
+			if exportOutput != "-" {
+				compression = export.CompressionFromPath(exportOutput)
+			}
+			writer, err = export.NewJSONLWriter(out, compression)
+		case "parquet":
+			writer, err = export.NewParquetWriter(out, "")
+		default:
+			writer, err = formatter.NewWordWriter(out, exportFormat)
+		}
+		if err != nil {
+			fmt.Fprintln(status, "🚨 Error:", err)
+			exit(1)
+		}
+
+		count := 0
+		repo := container.GetWordRepository()
+		err = repo.ForEachWordIn(exportBackend, filter, func(word *models.Word) error {
+			count++
+			return writer.Write(word)
+		})
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			fmt.Fprintln(status, "🚨 Error exporting words:", err)
+			exit(1)
+		}
+
+		target := exportOutput
+		if target == "-" {
+			target = "stdout"
+		}
+		fmt.Fprintf(status, "Exported %d words from %s to %s\n", count, exportBackend, target)
+	},
+}
+
+// parseExportDate parses a date flag as a day or an RFC 3339 time
+func parseExportDate(value string) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// completeExportFormats completes the registered output formats and the stream formats
+func completeExportFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions, directive := completeOutputFormats(cmd, args, toComplete)
+	for _, name := range []string{"jsonl", "parquet"} {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name+"\t"+streamFormats[name])
+		}
+	}
+	return completions, directive
+}
+
+// exportAnkiCmd writes stored words as an Anki flashcard deck
+var exportAnkiCmd = &cobra.Command{
+	Use:   "anki [words...]",
+	Short: "Export stored words as an Anki deck (.apkg)",
+	Long: `Exports stored words as an Anki package with one card per word. The front
+shows the word with its article; the back shows meanings, examples, plural,
+pronunciation audio and an image. Audio and images are taken from the local
+media store (see --media-dir and "media sync").
+
+Without arguments all stored words are exported. Note IDs are derived from
+the entry IDs, so importing a newer export updates existing cards.
+
+Templates use Anki's syntax and may reference the fields {{Word}}, {{Article}},
+{{WordType}}, {{Meanings}}, {{Examples}}, {{Plural}}, {{Audio}}, {{Image}}
+and {{ID}}.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Fail before reading all words if the binary cannot write the package
+		if err := export.CheckAnki(); err != nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+
+		template := export.DefaultAnkiTemplate()
+		if ankiNoteType != "" {
+			template.Name = ankiNoteType
+		}
+		for _, override := range []struct {
+			file   string
+			target *string
+		}{
+			{ankiFrontFile, &template.Front},
+			{ankiBackFile, &template.Back},
+			{ankiCSSFile, &template.CSS},
+		} {
+			if override.file == "" {
+				continue
+			}
+			data, err := os.ReadFile(override.file)
+			if err != nil {
+				fmt.Println("🚨 Error reading template:", err)
+				exit(1)
+			}
+			*override.target = string(data)
+		}
+
+		store, err := media.NewStore(mediaDir)
+		if err != nil {
+			fmt.Println("🚨 Error opening media store:", err)
+			exit(1)
+		}
+
+		exporter := export.NewAnkiExporter(export.AnkiOptions{
+			DeckName:   ankiDeckName,
+			Template:   template,
+			MediaStore: store,
+		})
+
+		if err := forEachExportWord(args, func(word *models.Word) error {
+			exporter.Add(word)
+			return nil
+		}); err != nil {
+			fmt.Println("🚨 Error reading stored words:", err)
+			exit(1)
+		}
+
+		if err := exporter.WriteFile(ankiOutput); err != nil {
+			fmt.Println("🚨 Error writing Anki package:", err)
+			exit(1)
+		}
+		fmt.Printf("Exported %d words to %s\n", exporter.Len(), ankiOutput)
+	},
+}
+
+// exportTableCmd writes stored words as one spreadsheet
+var exportTableCmd = &cobra.Command{
+	Use:   "table [words...]",
+	Short: "Export words as a single CSV, TSV or XLSX table",
+	Long: `Exports words as one table with a row per word. The columns are chosen
+with --columns. Without arguments all stored words are exported; with
+--from-dir the JSON files written by "bulk" are read instead of the database.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !formatter.IsTabular(tableFormat) {
+			fmt.Printf("🚨 Error: unsupported table format %q (use csv, tsv or xlsx)\n", tableFormat)
+			exit(1)
+		}
+		if tableOutput == "" {
+			tableOutput = "words." + tableFormat
+		}
+		columns, err := formatter.ParseColumns(columnList)
+		if err != nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+
+		out, err := os.Create(tableOutput)
+		if err != nil {
+			fmt.Println("🚨 Error creating output file:", err)
+			exit(1)
+		}
+		defer out.Close()
+
+		writer, err := formatter.NewTableWriter(out, tableFormat, columns)
+		if err != nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+
+		rows := 0
+		write := func(word *models.Word) error {
+			rows++
+			return writer.Write(word)
+		}
+
+		if tableFromDir != "" {
+			err = forEachFileWord(tableFromDir, write)
+		} else {
+			err = forEachExportWord(args, write)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			fmt.Println("🚨 Error writing table:", err)
+			exit(1)
+		}
+		fmt.Printf("Exported %d words to %s\n", rows, tableOutput)
+	},
+}
+
+// exportTEICmd writes words as a TEI Lex-0 dictionary
+var exportTEICmd = &cobra.Command{
+	Use:   "tei [words...]",
+	Short: "Export words as a TEI Lex-0 XML dictionary",
+	Long: `Exports words as a TEI Lex-0 document with one <entry> per word: lemma,
+inflected forms and pronunciation as <form>, part of speech and gender in
+<gramGrp> (normalized to Universal Dependencies and LexInfo values), nested
+<sense> elements with definitions and examples, <etym>, and cross-references
+to synonyms and antonyms. Idioms are encoded as related entries.
+
+Without arguments all stored words are exported; with --from-dir the JSON
+files written by "bulk" are read instead of the database.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if lexiconOutput == "" {
+			lexiconOutput = "lexicon.tei.xml"
+		}
+		runFileExport(args, lexiconOutput, lexiconFromDir, func(out *os.File) (export.Writer, error) {
+			return export.NewTEIWriter(out, lexiconTitle)
+		})
+	},
+}
+
+// exportOntoLexCmd writes words as OntoLex-Lemon RDF
+var exportOntoLexCmd = &cobra.Command{
+	Use:   "ontolex [words...]",
+	Short: "Export words as OntoLex-Lemon RDF (Turtle or JSON-LD)",
+	Long: `Exports words as an OntoLex-Lemon lexicon (lime:Lexicon). Each word becomes
+an ontolex:LexicalEntry with a canonical form (written and phonetic
+representation), other forms for its inflections, LexInfo part of speech and
+gender, and one ontolex:LexicalSense per meaning with skos:definition and
+skos:example. The etymology is given as skos:historyNote; synonyms and
+antonyms link to the entries of the linked words.
+
+Resources are named by appending entry IDs to --base.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if lexiconOutput == "" {
+			lexiconOutput = "lexicon.ttl"
+			if ontolexSyntax == export.SyntaxJSONLD {
+				lexiconOutput = "lexicon.jsonld"
+			}
+		}
+		runFileExport(args, lexiconOutput, lexiconFromDir, func(out *os.File) (export.Writer, error) {
+			return export.NewOntoLexWriter(out, ontolexSyntax, ontolexBase, lexiconTitle)
+		})
+	},
+}
+
+// exportStarDictCmd writes words as a StarDict or dictd dictionary
+var exportStarDictCmd = &cobra.Command{
+	Use:   "stardict [words...]",
+	Short: "Export words as a StarDict (or dictd) dictionary for offline lookup",
+	Long: `Builds a StarDict dictionary (.ifo, .idx, .syn, .dict.dz) for offline
+reader apps such as GoldenDict and KOReader. Entries are rendered with any
+output format (--entry-format, default html; see "formats"). Inflected forms
+such as plurals and verb forms are indexed as synonyms of their headword.
+
+With --dictd a dictd database (.index, .dict.dz) is written instead, with
+plain-text entries by default.
+
+Files are named after --output, e.g. -o dict/duden writes dict/duden.ifo.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if dir := filepath.Dir(dictOutput); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				fmt.Println("🚨 Error creating output directory:", err)
+				exit(1)
+			}
+		}
+
+		options := export.DictionaryOptions{
+			Name:        dictName,
+			Description: dictDescription,
+			Format:      dictFormat,
+			Compress:    dictCompress,
+		}
+
+		var writer export.Writer
+		var err error
+		if dictdFormat {
+			writer, err = export.NewDictdWriter(dictOutput, options)
+		} else {
+			writer, err = export.NewStarDictWriter(dictOutput, options)
+		}
+		if err != nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+
+		count := 0
+		write := func(word *models.Word) error {
+			count++
+			return writer.Write(word)
+		}
+
+		if dictFromDir != "" {
+			err = forEachFileWord(dictFromDir, write)
+		} else {
+			err = forEachExportWord(args, write)
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			fmt.Println("🚨 Error writing dictionary:", err)
+			exit(1)
+		}
+		fmt.Printf("Exported %d words to %s.*\n", count, dictOutput)
+	},
+}
+
+// exportJSONLCmd writes words as one JSON Lines stream
+var exportJSONLCmd = &cobra.Command{
+	Use:   "jsonl [words...]",
+	Short: "Export words as a JSON Lines stream (optionally gzip or zstd)",
+	Long: `Exports words as JSON Lines: one complete word document per line, with a
+"schema_version" field (see "Export Schema" in the README). The stream is
+compressed according to --compression, or by default the file extension:
+.gz for gzip, .zst for zstd.
+
+Without arguments all stored words are exported; with --from-dir the JSON
+files written by "bulk" are read instead of the database.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		compression := jsonlCompression
+		if compression == "" {
+			compression = export.CompressionFromPath(jsonlOutput)
+		}
+		runFileExport(args, jsonlOutput, streamFromDir, func(out *os.File) (export.Writer, error) {
+			return export.NewJSONLWriter(out, compression)
+		})
+	},
+}
+
+// exportParquetCmd writes words as a Parquet file
+var exportParquetCmd = &cobra.Command{
+	Use:   "parquet [words...]",
+	Short: "Export words as an Apache Parquet file with a flattened schema",
+	Long: `Exports words as an Apache Parquet file with one row per word. Nested data
+is flattened into string columns and lists of strings; the columns are
+documented under "Export Schema" in the README and versioned by the
+schema_version column.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runFileExport(args, parquetOutput, streamFromDir, func(out *os.File) (export.Writer, error) {
+			return export.NewParquetWriter(out, parquetCompression)
+		})
+	},
+}
+
+// runFileExport streams words into the writer created for the output file
+func runFileExport(args []string, output, fromDir string, newWriter func(out *os.File) (export.Writer, error)) {
+	out, err := os.Create(output)
+	if err != nil {
+		fmt.Println("🚨 Error creating output file:", err)
+		exit(1)
+	}
+	defer out.Close()
+
+	writer, err := newWriter(out)
+	if err != nil {
+		fmt.Println("🚨 Error:", err)
+		exit(1)
+	}
+
+	count := 0
+	write := func(word *models.Word) error {
+		count++
+		return writer.Write(word)
+	}
+
+	if fromDir != "" {
+		err = forEachFileWord(fromDir, write)
+	} else {
+		err = forEachExportWord(args, write)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		fmt.Println("🚨 Error writing export:", err)
+		exit(1)
+	}
+	fmt.Printf("Exported %d words to %s\n", count, output)
+}
+
+// forEachFileWord calls fn for every JSON word file in dir, such as the output of "bulk"
+func forEachFileWord(dir string, fn func(word *models.Word) error) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var word models.Word
+		if err := json.Unmarshal(data, &word); err != nil || strings.TrimSpace(word.Word) == "" {
+			fmt.Printf("⚠️  Skipping %s: not a word file\n", file)
+			continue
+		}
+		if err := fn(&word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachExportWord calls fn for the named stored words, or for all stored
+// words when no names are given. Names that are not stored are reported and skipped.
+func forEachExportWord(names []string, fn func(word *models.Word) error) error {
+	repo := container.GetWordRepository()
+	if len(names) == 0 {
+		return repo.ForEachWord(fn)
+	}
+
+	for _, name := range names {
+		word, err := repo.GetWord(name)
+		if err != nil {
+			fmt.Printf("⚠️  Skipping %q: %v\n", name, err)
+			continue
+		}
+		if err := fn(word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportAnkiCmd)
+	exportCmd.AddCommand(exportTableCmd)
+	exportCmd.AddCommand(exportTEICmd)
+	exportCmd.AddCommand(exportOntoLexCmd)
+	exportCmd.AddCommand(exportStarDictCmd)
+	exportCmd.AddCommand(exportJSONLCmd)
+	exportCmd.AddCommand(exportParquetCmd)
+
+	exportCmd.Flags().StringVar(&exportBackend, "from", "mongodb", "Backend to read words from ("+strings.Join(repository.BackendNames(), ", ")+")")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "jsonl", "Output format (jsonl, parquet, "+strings.Join(outputFormats.Names(), ", ")+")")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "-", "File to write, or - for stdout")
+	exportCmd.Flags().StringSliceVar(&exportWordTypes, "word-type", nil, "Only export words of these types (e.g. Substantiv, verb)")
+	exportCmd.Flags().StringSliceVar(&exportFrequencies, "frequency", nil, "Only export words with these Duden frequencies")
+	exportCmd.Flags().StringVar(&exportFetchedAfter, "fetched-after", "", "Only export words fetched on or after this date")
+	exportCmd.Flags().StringVar(&exportFetchedBefore, "fetched-before", "", "Only export words fetched before this date")
+	exportCmd.RegisterFlagCompletionFunc("format", completeExportFormats)
+	exportCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return repository.BackendNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	formatFlags[exportCmd] = "format"
+
+	exportAnkiCmd.Flags().StringVarP(&ankiOutput, "output", "o", "goden-crawler.apkg", "Package file to write")
+	exportAnkiCmd.Flags().StringVar(&ankiDeckName, "deck", "Goden Crawler", "Name of the Anki deck")
+	exportAnkiCmd.Flags().StringVar(&ankiNoteType, "note-type", "", "Name of the Anki note type (keep it stable to update cards)")
+	exportAnkiCmd.Flags().StringVar(&ankiFrontFile, "front", "", "File with the front template")
+	exportAnkiCmd.Flags().StringVar(&ankiBackFile, "back", "", "File with the back template")
+	exportAnkiCmd.Flags().StringVar(&ankiCSSFile, "css", "", "File with the card styling")
+
+	exportTableCmd.Flags().StringVarP(&tableFormat, "format", "f", "csv", "Table format (csv, tsv, xlsx)")
+	exportTableCmd.Flags().StringVarP(&tableOutput, "output", "o", "", "File to write (default words.<format>)")
+	exportTableCmd.Flags().StringVar(&tableFromDir, "from-dir", "", "Read JSON word files from this directory (e.g. bulk output) instead of the database")
+
+	for _, lexiconCmd := range []*cobra.Command{exportTEICmd, exportOntoLexCmd} {
+		lexiconCmd.Flags().StringVarP(&lexiconOutput, "output", "o", "", "File to write")
+		lexiconCmd.Flags().StringVar(&lexiconTitle, "title", "Goden Crawler Dictionary", "Title of the dictionary")
+		lexiconCmd.Flags().StringVar(&lexiconFromDir, "from-dir", "", "Read JSON word files from this directory (e.g. bulk output) instead of the database")
+	}
+	exportOntoLexCmd.Flags().StringVar(&ontolexSyntax, "syntax", export.SyntaxTurtle, "RDF syntax (turtle, jsonld)")
+	exportOntoLexCmd.Flags().StringVar(&ontolexBase, "base", export.DefaultOntoLexBase, "IRI prefix of the exported resources")
+
+	exportStarDictCmd.Flags().StringVarP(&dictOutput, "output", "o", "goden-crawler", "Path of the dictionary files without extension")
+	exportStarDictCmd.Flags().StringVar(&dictName, "name", "Goden Crawler", "Dictionary name shown by reader apps")
+	exportStarDictCmd.Flags().StringVar(&dictDescription, "description", "", "Dictionary description")
+	exportStarDictCmd.Flags().StringVar(&dictFormat, "entry-format", "", "Output format of the entries (default html, or text with --dictd)")
+	exportStarDictCmd.Flags().BoolVar(&dictCompress, "compress", true, "Compress the .dict file with dictzip")
+	exportStarDictCmd.Flags().BoolVar(&dictdFormat, "dictd", false, "Write a dictd database instead of StarDict")
+	exportStarDictCmd.Flags().StringVar(&dictFromDir, "from-dir", "", "Read JSON word files from this directory (e.g. bulk output) instead of the database")
+	exportStarDictCmd.RegisterFlagCompletionFunc("entry-format", completeOutputFormats)
+
+	exportJSONLCmd.Flags().StringVarP(&jsonlOutput, "output", "o", "words.jsonl", "File to write (.gz or .zst compresses)")
+	exportJSONLCmd.Flags().StringVar(&jsonlCompression, "compression", "", "Compression: none, gzip or zstd (default from the file extension)")
+	exportParquetCmd.Flags().StringVarP(&parquetOutput, "output", "o", "words.parquet", "File to write")
+	exportParquetCmd.Flags().StringVar(&parquetCompression, "compression", "snappy", "Compression: none, snappy, gzip or zstd")
+	for _, streamCmd := range []*cobra.Command{exportJSONLCmd, exportParquetCmd} {
+		streamCmd.Flags().StringVar(&streamFromDir, "from-dir", "", "Read JSON word files from this directory (e.g. bulk output) instead of the database")
+	}
+}
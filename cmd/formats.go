@@ -0,0 +1,66 @@
+// File: cmd/formats.go
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
+	"github.com/spf13/cobra"
+)
+
+// outputFormats resolves --output values. Flag help and completion list the
+// built-in formats; formats provided by plugins are added to the registry when
+// the root command loads the plugins before running a command.
+var outputFormats = formatter.Default()
+
+// outputFlagUsage returns the usage text of a format flag listing the built-in formats
+func outputFlagUsage() string {
+	return fmt.Sprintf("Output format (%s)", strings.Join(outputFormats.Names(), ", "))
+}
+
+// formatFlags maps commands to the name of their format flag
+var formatFlags = make(map[*cobra.Command]string)
+
+// addFormatFlag defines a format flag with completion of the built-in formats
+func addFormatFlag(cmd *cobra.Command, target *string, name, shorthand, defaultFormat string) {
+	cmd.Flags().StringVarP(target, name, shorthand, defaultFormat, outputFlagUsage())
+	cmd.RegisterFlagCompletionFunc(name, completeOutputFormats)
+	formatFlags[cmd] = name
+}
+
+// completeOutputFormats completes format names with their descriptions
+func completeOutputFormats(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, registration := range outputFormats.All() {
+		if strings.HasPrefix(registration.Name, toComplete) {
+			completions = append(completions, registration.Name+"\t"+registration.Description)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// outputExtension returns the file extension of a format, e.g. ".json"
+func outputExtension(format string) string {
+	if registration, exists := outputFormats.Get(format); exists {
+		return registration.Extension
+	}
+	return "." + format
+}
+
+// formatsCmd lists the registered output formats
+var formatsCmd = &cobra.Command{
+	Use:   "formats",
+	Short: "List the available output formats",
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, registration := range outputFormats.All() {
+			fmt.Printf("%-8s %-6s %-40s %s\n", registration.Name, registration.Extension,
+				registration.Description, strings.SplitN(registration.MIMEType, ";", 2)[0])
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(formatsCmd)
+}
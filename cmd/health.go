@@ -0,0 +1,91 @@
+// File: cmd/health.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var healthJSON bool
+
+// healthCmd reports the state of every database
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Check the health of database connections",
+	Long: `Connects to MongoDB, PostgreSQL, Redis and Elasticsearch and prints the state,
+latency and server version of each.
+
+Databases that are down are skipped with a warning while the other commands
+run and are reconnected periodically. Only the backends in REQUIRED_BACKENDS
+(comma-separated, default "mongodb") must be available: health exits with
+status 1 when one of them is down.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Keep the table and JSON free of connection warnings
+		logger.GetGlobalLogger().SetOutput(os.Stderr)
+
+		statuses := container.GetWordRepository().Health()
+
+		if healthJSON {
+			data, err := json.MarshalIndent(statuses, "", "  ")
+			if err != nil {
+				fmt.Println("🚨 Error encoding JSON:", err)
+				exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printHealth(statuses)
+		}
+
+		for _, status := range statuses {
+			if status.Required && status.State != health.StateUp {
+				exit(1)
+			}
+		}
+	},
+}
+
+// printHealth prints one line per backend
+func printHealth(statuses []health.Status) {
+	fmt.Printf("%-14s %-6s %10s  %-10s %-8s %s\n", "BACKEND", "STATE", "LATENCY", "VERSION", "REQUIRED", "ERROR")
+
+	var down []string
+	for _, status := range statuses {
+		latency, version := "-", "-"
+		if status.State == health.StateUp {
+			latency = fmt.Sprintf("%.1fms", status.Latency)
+		}
+		if status.Version != "" {
+			version = status.Version
+		}
+		required := "no"
+		if status.Required {
+			required = "yes"
+		}
+		fmt.Printf("%-14s %-6s %10s  %-10s %-8s %s\n", status.Backend, status.State, latency, version, required, status.Error)
+
+		if status.Required && status.State != health.StateUp {
+			down = append(down, status.Backend)
+		}
+	}
+
+	if len(down) > 0 {
+		fmt.Printf("\n🚨 Required backends unavailable: %s\n", strings.Join(down, ", "))
+		return
+	}
+	fmt.Println("\n✅ All required backends are available.")
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+
+	healthCmd.Flags().BoolVar(&healthJSON, "json", false, "Print the status of each backend as JSON")
+}
@@ -0,0 +1,90 @@
+// File: cmd/import.go
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/export"
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// importCmd loads dumps into all backends
+var importCmd = &cobra.Command{
+	Use:   "import <files or directories...>",
+	Short: "Load JSON or JSON Lines dumps into all databases",
+	Long: `Loads words from dumps into MongoDB, PostgreSQL, Redis and Elasticsearch.
+Accepted are JSON Lines files as written by "export" (optionally compressed
+as .gz or .zst), JSON arrays of words, and JSON files written by "scrape" or
+"bulk". Directories are searched for *.json, *.jsonl, *.jsonl.gz and
+*.jsonl.zst files.
+
+Words are saved by entry ID, so importing a dump twice updates the stored
+words instead of duplicating them. Records written with a newer schema
+version than this build supports are rejected.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		files, err := importFiles(args)
+		if err != nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+
+		repo := container.GetWordRepository()
+		imported, failed := 0, 0
+		for _, file := range files {
+			err := export.ReadDumpFile(file, func(word *models.Word) error {
+				if err := repo.SaveWord(word); err != nil {
+					fmt.Printf("⚠️  Failed to import %q: %v\n", word.Word, err)
+					failed++
+					return nil
+				}
+				imported++
+				return nil
+			})
+			if err != nil {
+				fmt.Printf("🚨 Error reading %s: %v\n", file, err)
+				failed++
+			}
+		}
+
+		fmt.Printf("Imported %d words from %d files (%d failed)\n", imported, len(files), failed)
+		if failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+// importPatterns are the dump files read from directories
+var importPatterns = []string{"*.json", "*.jsonl", "*.jsonl.gz", "*.jsonl.zst"}
+
+// importFiles expands directories in args to the dump files they contain
+func importFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		for _, pattern := range importPatterns {
+			matches, err := filepath.Glob(filepath.Join(arg, pattern))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+	}
+	return files, nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+}
@@ -4,13 +4,16 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/amirhossein-jamali/goden-crawler/internal/application/services"
 	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 
 	"github.com/spf13/cobra"
 )
@@ -27,6 +30,9 @@ Type 'help' to see available commands.`,
 		// Get word service from container
 		wordService := container.GetWordService()
 
+		// Words saved by other instances replace cached copies
+		container.WatchInvalidations(context.Background())
+
 		// Start interactive mode
 		fmt.Println("🔍 Goden Crawler Interactive Mode")
 		fmt.Println("Type a German word to get information, or 'exit' to quit.")
@@ -63,15 +69,17 @@ Type 'help' to see available commands.`,
 					fmt.Printf("  %d. %s\n", i+1, section)
 				}
 				continue
+			}
 
-			case "format json":
-				interactiveFormat = "json"
-				fmt.Println("🔄 Output format set to JSON")
-				continue
-
-			case "format text":
-				interactiveFormat = "text"
-				fmt.Println("🔄 Output format set to text")
+			// Switch to any registered output format, e.g. "format json"
+			if strings.HasPrefix(input, "format ") {
+				name := strings.TrimSpace(strings.TrimPrefix(input, "format "))
+				if _, exists := outputFormats.Get(name); !exists {
+					fmt.Printf("⚠️  Unknown format %q. Available: %s\n", name, strings.Join(outputFormats.Names(), ", "))
+					continue
+				}
+				interactiveFormat = name
+				fmt.Printf("🔄 Output format set to %s\n", name)
 				continue
 			}
 
@@ -82,8 +90,22 @@ Type 'help' to see available commands.`,
 				continue
 			}
 
+			// List the homonym entries of a word, even if one of them is stored
+			if strings.HasPrefix(input, "entries ") {
+				word := strings.TrimSpace(strings.TrimPrefix(input, "entries "))
+				handleEntryChoice(wordService, scanner, word, interactiveFormat)
+				continue
+			}
+
+			// Check if it's an etymology query
+			if strings.HasPrefix(input, "origin ") {
+				language := strings.TrimPrefix(input, "origin ")
+				handleOrigin(wordService, language)
+				continue
+			}
+
 			// Otherwise, treat as a word to scrape
-			handleWord(wordService, input, interactiveFormat)
+			handleWord(wordService, scanner, input, interactiveFormat)
 		}
 
 		if err := scanner.Err(); err != nil {
@@ -92,17 +114,51 @@ Type 'help' to see available commands.`,
 	},
 }
 
-func handleWord(wordService *services.WordService, word, format string) {
+// handleWord prints a word, from the repository if it is stored. Only words
+// that are not stored cost a request to discover their homonym entries.
+func handleWord(wordService *services.WordService, scanner *bufio.Scanner, word, format string) {
 	fmt.Printf("🔍 Fetching information for '%s'...\n", word)
 
+	if wordData, err := wordService.GetStoredWord(word); err == nil {
+		printWord(wordData, format)
+		fmt.Printf("💡 Type 'entries %s' to choose among other entries of the word.\n", word)
+		return
+	}
+
+	// Let the user pick an entry when the word has several homonyms
+	entries, err := wordService.GetWordEntries(word)
+	if err == nil && len(entries) > 1 {
+		handleEntries(wordService, scanner, entries, format)
+		return
+	}
+
 	// Fetch word data using the service
 	wordData, err := wordService.GetWordData(word)
 	if err != nil {
 		fmt.Println("🚨 Error:", err)
 		return
 	}
+	printWord(wordData, format)
+}
+
+// handleEntryChoice lists the homonym entries of a word on Duden and prints the selected one(s)
+func handleEntryChoice(wordService *services.WordService, scanner *bufio.Scanner, word, format string) {
+	fmt.Printf("🔍 Finding entries for '%s'...\n", word)
+
+	entries, err := wordService.GetWordEntries(word)
+	if err != nil {
+		fmt.Println("🚨 Error:", err)
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Println("❌ No entries found.")
+		return
+	}
+	handleEntries(wordService, scanner, entries, format)
+}
 
-	// Format output based on user selection
+// printWord prints a word in the given format
+func printWord(wordData *models.Word, format string) {
 	output, err := formatter.FormatOutput(wordData, format)
 	if err != nil {
 		fmt.Println("🚨 Error formatting output:", err)
@@ -112,6 +168,43 @@ func handleWord(wordService *services.WordService, word, format string) {
 	fmt.Println(output)
 }
 
+// handleEntries shows a chooser for homonym entries and prints the selected one(s)
+func handleEntries(wordService *services.WordService, scanner *bufio.Scanner, entries []models.Entry, format string) {
+	fmt.Printf("📋 Found %d entries:\n", len(entries))
+	for i, entry := range entries {
+		title := entry.Title
+		if title == "" {
+			title = entry.Slug
+		}
+		fmt.Printf("  %d. %s (%s)\n", i+1, title, entry.Slug)
+	}
+	fmt.Printf("Choose an entry [1-%d], 'a' for all: ", len(entries))
+
+	if !scanner.Scan() {
+		return
+	}
+	choice := strings.TrimSpace(scanner.Text())
+
+	selected := entries
+	if !strings.EqualFold(choice, "a") {
+		index, err := strconv.Atoi(choice)
+		if err != nil || index < 1 || index > len(entries) {
+			fmt.Println("❌ Invalid choice.")
+			return
+		}
+		selected = entries[index-1 : index]
+	}
+
+	for _, entry := range selected {
+		wordData, err := wordService.GetEntryData(entry.Slug)
+		if err != nil {
+			fmt.Println("🚨 Error:", err)
+			continue
+		}
+		printWord(wordData, format)
+	}
+}
+
 func handleSuggestions(wordService *services.WordService, word string) {
 	fmt.Printf("🔍 Finding suggestions for '%s'...\n", word)
 
@@ -132,12 +225,33 @@ func handleSuggestions(wordService *services.WordService, word string) {
 	}
 }
 
+func handleOrigin(wordService *services.WordService, language string) {
+	fmt.Printf("🔍 Finding stored words of %s origin...\n", language)
+
+	words, err := wordService.GetWordsByOrigin(language)
+	if err != nil {
+		fmt.Println("🚨 Error:", err)
+		return
+	}
+
+	if len(words) == 0 {
+		fmt.Println("❌ No words found.")
+		return
+	}
+
+	fmt.Printf("📋 Found %d words:\n", len(words))
+	for i, word := range words {
+		fmt.Printf("  %d. %s\n", i+1, word.Word)
+	}
+}
+
 func printHelp() {
 	fmt.Println("📚 Available commands:")
-	fmt.Println("  [word]           - Fetch information for a German word")
+	fmt.Println("  [word]           - Fetch information for a German word (stored words first)")
+	fmt.Println("  entries [word]   - Choose among the homonym entries of a word on Duden")
 	fmt.Println("  suggest [word]   - Get suggestions for a word")
-	fmt.Println("  format json      - Set output format to JSON")
-	fmt.Println("  format text      - Set output format to text")
+	fmt.Println("  origin [lang]    - List stored words from a language (e.g. französisch)")
+	fmt.Println("  format [name]    - Set output format (" + strings.Join(outputFormats.Names(), ", ") + ")")
 	fmt.Println("  sections         - List available data sections")
 	fmt.Println("  help             - Show this help message")
 	fmt.Println("  exit, quit       - Exit the program")
@@ -0,0 +1,68 @@
+// File: cmd/media.go
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// mediaCmd groups commands for the local media store
+var mediaCmd = &cobra.Command{
+	Use:   "media",
+	Short: "Manage downloaded pronunciation audio and images",
+}
+
+// mediaSyncCmd downloads missing media for words that are already stored
+var mediaSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download media for all stored words",
+	Long: `Downloads the pronunciation MP3s and meaning images of every stored word
+into the content-addressed media store (see --media-dir) and records their
+checksum, size and local path on the word. Assets that are already stored
+are not downloaded again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pipeline, err := container.GetMediaPipeline(mediaDir)
+		if err != nil {
+			fmt.Println("🚨 Error opening media store:", err)
+			exit(1)
+		}
+		repo := container.GetWordRepository()
+
+		// Collect first so that writes do not interfere with the open cursor
+		var changed []*models.Word
+		scanned := 0
+		err = repo.ForEachWord(func(word *models.Word) error {
+			scanned++
+			if pipeline.Process(word) {
+				changed = append(changed, word)
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Println("🚨 Error reading stored words:", err)
+			exit(1)
+		}
+
+		failed := 0
+		for _, word := range changed {
+			if err := repo.SaveWord(word); err != nil {
+				failed++
+			}
+		}
+
+		fmt.Printf("Scanned %d words, updated %d (%d failed). Media stored in %s\n",
+			scanned, len(changed)-failed, failed, pipeline.Store().Root())
+		if failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mediaCmd)
+	mediaCmd.AddCommand(mediaSyncCmd)
+}
@@ -0,0 +1,112 @@
+// File: cmd/migrate.go
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/crawler/extractors"
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var migrateDryRun bool
+
+// migrateCmd groups one-time data migrations
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Run one-time data migrations against all databases",
+}
+
+// stripSentinelsCmd removes legacy placeholder values from stored documents
+var stripSentinelsCmd = &cobra.Command{
+	Use:   "strip-sentinels",
+	Short: "Remove placeholder values like \"n/a\" from stored words",
+	Long: `Older versions stored placeholder strings such as "n/a", "no_audio_available",
+"No data available", "N/A" and "unknown" in place of missing data.
+This migration removes them from every document in MongoDB, PostgreSQL,
+Redis and Elasticsearch so that absent data is simply omitted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := container.GetWordRepository()
+		printMigrationResults(repo.StripSentinels(migrateDryRun))
+	},
+}
+
+// assignIDsCmd backfills entry IDs and lookup keys on documents keyed by word
+var assignIDsCmd = &cobra.Command{
+	Use:   "assign-ids",
+	Short: "Give stored words an entry ID and lookup key",
+	Long: `Older versions keyed every backend by the display word, so homonyms
+overwrote each other and "haus" did not find "Haus". Entries are now keyed
+by their Duden URL slug with a case- and umlaut-insensitive lookup key.
+This migration assigns the display word as ID to documents that have none
+and fills in the lookup key in MongoDB, PostgreSQL, Redis and Elasticsearch.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := container.GetWordRepository()
+		printMigrationResults(repo.AssignIdentity(migrateDryRun))
+	},
+}
+
+// etymologyCmd converts the origin of older documents into an etymology
+var etymologyCmd = &cobra.Command{
+	Use:   "etymology",
+	Short: "Convert the origin of stored words into an etymology",
+	Long: `Older versions stored the origin ("Herkunft") of a word as a list of its
+words and links. It is now an etymology with readable text and
+language-tagged source words, so the origin of older documents is not shown
+and searches by origin language do not find them.
+
+This migration first updates the Elasticsearch mapping. Etymology sources are
+searched as nested documents, which an existing index may have mapped as plain
+objects; such an index is rebuilt with the current mapping, and searches miss
+its words until the rebuild is done. It then converts the origin of every
+document in MongoDB, PostgreSQL, Redis and Elasticsearch.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo := container.GetWordRepository()
+
+		// The other backends are converted even if Elasticsearch cannot be updated
+		outcome, mappingErr := repo.UpdateSearchMapping(migrateDryRun)
+		if mappingErr != nil {
+			fmt.Printf("%-14s mapping: error: %v\n", "elasticsearch", mappingErr)
+		} else {
+			fmt.Printf("%-14s mapping: %s\n", "elasticsearch", outcome)
+		}
+
+		printMigrationResults(repo.ConvertOrigin(migrateDryRun, extractors.EtymologyFromOrigin))
+		if mappingErr != nil {
+			exit(1)
+		}
+	},
+}
+
+// printMigrationResults prints one line per backend and exits non-zero on failure
+func printMigrationResults(results []repository.MigrationResult) {
+	failed := false
+	for _, result := range results {
+		status := "ok"
+		if result.Err != nil {
+			status = fmt.Sprintf("error: %v", result.Err)
+			failed = true
+		}
+		fmt.Printf("%-14s scanned: %d, updated: %d (%s)\n",
+			result.Backend, result.Scanned, result.Updated, status)
+	}
+
+	if migrateDryRun {
+		fmt.Println("Dry run: no documents were modified.")
+	}
+
+	if failed {
+		exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(stripSentinelsCmd)
+	migrateCmd.AddCommand(assignIDsCmd)
+	migrateCmd.AddCommand(etymologyCmd)
+
+	migrateCmd.PersistentFlags().BoolVar(&migrateDryRun, "dry-run", false, "Report what would change without writing")
+}
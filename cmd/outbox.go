@@ -0,0 +1,205 @@
+// File: cmd/outbox.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/internal/outbox"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outboxBackend string
+	outboxDead    bool
+	outboxLimit   int
+	outboxJSON    bool
+	outboxOnce    bool
+)
+
+// outboxCmd groups commands inspecting the queue of database writes
+var outboxCmd = &cobra.Command{
+	Use:   "outbox",
+	Short: "Inspect and process queued database writes",
+	Long: `Saved words are recorded in an outbox (OUTBOX=mongodb, the default, keeps it
+in MongoDB; OUTBOX=file in the directory OUTBOX_DIR; OUTBOX=off writes all
+databases synchronously). MongoDB is written while saving; PostgreSQL, Redis
+and Elasticsearch are updated by background workers, which retry failed
+writes with exponential backoff. Writes failing OUTBOX_MAX_ATTEMPTS times are
+moved to the dead letters.`,
+}
+
+// outboxStatusCmd prints pending and failed writes per backend
+var outboxStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show pending and dead-lettered writes per backend",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dispatcher := requireOutbox()
+		stats, err := dispatcher.Store().Stats()
+		if err != nil {
+			fmt.Println("🚨 Error reading outbox:", err)
+			exit(1)
+		}
+
+		if outboxJSON {
+			printOutboxJSON(stats)
+			return
+		}
+		if len(stats) == 0 {
+			fmt.Println("✅ No queued writes.")
+			return
+		}
+
+		fmt.Printf("%-14s %8s %9s %6s  %-20s %s\n", "BACKEND", "PENDING", "RETRYING", "DEAD", "OLDEST PENDING", "NEXT ATTEMPT")
+		for _, s := range stats {
+			fmt.Printf("%-14s %8d %9d %6d  %-20s %s\n", s.Backend, s.Pending, s.Retrying, s.Dead,
+				formatOutboxTime(s.OldestPending), formatOutboxTime(s.NextAttempt))
+		}
+	},
+}
+
+// outboxListCmd lists queued writes with their last error
+var outboxListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pending or dead-lettered writes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dispatcher := requireOutbox()
+		status := outbox.StatusPending
+		if outboxDead {
+			status = outbox.StatusDead
+		}
+
+		entries, err := dispatcher.Store().List(status, outboxBackend, outboxLimit)
+		if err != nil {
+			fmt.Println("🚨 Error reading outbox:", err)
+			exit(1)
+		}
+
+		if outboxJSON {
+			for i := range entries {
+				entries[i].Word = nil
+			}
+			printOutboxJSON(entries)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No %s writes.\n", status)
+			return
+		}
+
+		for _, entry := range entries {
+			line := fmt.Sprintf("%-14s %-30s attempts: %d, queued %s", entry.Backend, entry.WordID,
+				entry.Attempts, formatOutboxTime(entry.CreatedAt))
+			if status == outbox.StatusPending && entry.Attempts > 0 {
+				line += ", next attempt " + formatOutboxTime(entry.NextAttempt)
+			}
+			fmt.Println(line)
+			if entry.LastError != "" {
+				fmt.Printf("    error: %s\n", entry.LastError)
+			}
+		}
+	},
+}
+
+// outboxRunCmd applies queued writes
+var outboxRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Apply queued writes, continuously or once",
+	Long: `Applies queued writes to the databases. By default the command keeps running
+and polls for new and retried writes until interrupted, so it can run as a
+daemon next to crawler processes. With --once it exits when no writes are due.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dispatcher := requireOutbox()
+
+		if outboxOnce {
+			for {
+				processed, err := dispatcher.ProcessDue()
+				if err != nil {
+					fmt.Println("🚨 Error processing outbox:", err)
+					exit(1)
+				}
+				if processed == 0 {
+					break
+				}
+			}
+			fmt.Println("No writes are due.")
+			return
+		}
+
+		dispatcher.Start()
+		fmt.Println("Processing the outbox, press Ctrl+C to stop...")
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+	},
+}
+
+// outboxRetryCmd moves dead letters back into the queue
+var outboxRetryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Queue dead-lettered writes again",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dispatcher := requireOutbox()
+		requeued, err := dispatcher.Store().Requeue(outboxBackend)
+		if err != nil {
+			fmt.Println("🚨 Error requeuing writes:", err)
+			exit(1)
+		}
+		fmt.Printf("Requeued %d writes.\n", requeued)
+	},
+}
+
+// requireOutbox returns the outbox dispatcher or exits if writes are synchronous
+func requireOutbox() *outbox.Dispatcher {
+	dispatcher := container.GetWordRepository().Outbox()
+	if dispatcher == nil {
+		fmt.Println("🚨 Error: the outbox is disabled (OUTBOX=off)")
+		exit(1)
+	}
+	return dispatcher
+}
+
+// formatOutboxTime formats a time for tables, or "-" for none
+func formatOutboxTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// printOutboxJSON prints a value as indented JSON
+func printOutboxJSON(value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Println("🚨 Error encoding JSON:", err)
+		exit(1)
+	}
+	fmt.Println(string(data))
+}
+
+func init() {
+	rootCmd.AddCommand(outboxCmd)
+	outboxCmd.AddCommand(outboxStatusCmd)
+	outboxCmd.AddCommand(outboxListCmd)
+	outboxCmd.AddCommand(outboxRunCmd)
+	outboxCmd.AddCommand(outboxRetryCmd)
+
+	outboxStatusCmd.Flags().BoolVar(&outboxJSON, "json", false, "Print the status as JSON")
+	outboxListCmd.Flags().BoolVar(&outboxJSON, "json", false, "Print the entries as JSON (without words)")
+	outboxListCmd.Flags().BoolVar(&outboxDead, "dead", false, "List dead-lettered instead of pending writes")
+	outboxListCmd.Flags().IntVar(&outboxLimit, "limit", 50, "Maximum number of entries (0 for all)")
+	for _, c := range []*cobra.Command{outboxListCmd, outboxRetryCmd} {
+		c.Flags().StringVar(&outboxBackend, "backend", "", "Only entries of this backend")
+	}
+	outboxRunCmd.Flags().BoolVar(&outboxOnce, "once", false, "Exit when no writes are due")
+}
@@ -0,0 +1,251 @@
+// File: cmd/refresh.go
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/application/services"
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	refreshOlderThan time.Duration
+	refreshPriority  string
+	refreshLimit     int
+	refreshDryRun    bool
+	refreshDaemon    bool
+	refreshInterval  time.Duration
+	refreshJSON      bool
+)
+
+// refreshCmd crawls stale stored words again
+var refreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Crawl stored words again once they are older than a maximum age",
+	Long: `Stored words are served from the databases and never crawled again on their
+own. This command crawls the words fetched longer than --older-than ago again,
+most important first:
+
+- frequency: words Duden rates as most frequent
+- access:    words read most often from the databases
+
+Words of equal priority are refreshed oldest first. When Duden's content
+changed, the previous version is kept (see "refresh history") and the revision
+of the word is increased; otherwise only its fetch time is updated.
+
+With --daemon the refresh runs every --interval until interrupted.`,
+	Example: `  goden-crawler refresh --older-than 720h --limit 100
+  goden-crawler refresh --priority access --dry-run
+  goden-crawler refresh --daemon --interval 1h --limit 50
+  goden-crawler refresh history Haus`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if refreshJSON {
+			logger.GetGlobalLogger().SetOutput(os.Stderr)
+		}
+		if refreshOlderThan < 0 || refreshLimit < 0 || (refreshDaemon && refreshInterval <= 0) {
+			fmt.Println("🚨 Error: --older-than and --limit must not be negative and --interval must be positive")
+			exit(1)
+		}
+
+		refreshService := container.GetRefreshService()
+		if downloadMedia {
+			pipeline, err := container.GetMediaPipeline(mediaDir)
+			if err != nil {
+				fmt.Println("🚨 Error opening media store:", err)
+				exit(1)
+			}
+			refreshService.WithMedia(pipeline)
+		}
+		options := services.RefreshOptions{
+			MaxAge:   refreshOlderThan,
+			Priority: refreshPriority,
+			Limit:    refreshLimit,
+		}
+
+		if refreshDryRun {
+			listStaleWords(refreshService, options)
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if !refreshDaemon {
+			summary, err := refreshService.Refresh(ctx, options)
+			if summary == nil {
+				fmt.Println("🚨 Error refreshing words:", err)
+				exit(1)
+			}
+			printRefreshSummary(summary)
+			if err != nil || summary.Failed > 0 {
+				exit(1)
+			}
+			return
+		}
+
+		container.WatchInvalidations(ctx)
+		fmt.Printf("Refreshing words older than %s every %s, press Ctrl+C to stop...\n", refreshOlderThan, refreshInterval)
+		for {
+			summary, err := refreshService.Refresh(ctx, options)
+			if summary != nil {
+				printRefreshSummary(summary)
+			} else {
+				fmt.Println("⚠️  Refresh failed, trying again at the next interval:", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(refreshInterval):
+			}
+		}
+	},
+}
+
+// refreshHistoryCmd lists the previous versions of a word
+var refreshHistoryCmd = &cobra.Command{
+	Use:   "history <word>",
+	Short: "List the previous versions of a stored word",
+	Long: `Lists the versions of a word that were replaced because Duden's content
+changed when it was refreshed, newest first. The word may be given as entry
+ID or in any spelling; "haus" finds "Haus".`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if refreshJSON {
+			logger.GetGlobalLogger().SetOutput(os.Stderr)
+		}
+		wordRepository := container.GetWordRepository()
+
+		word, err := wordRepository.GetStoredWord(args[0])
+		if err != nil {
+			fmt.Println("🚨 Error reading word:", err)
+			exit(1)
+		}
+		versions, err := wordRepository.GetWordVersions(word.ID)
+		if err != nil {
+			fmt.Println("🚨 Error reading previous versions:", err)
+			exit(1)
+		}
+
+		if refreshJSON {
+			data, err := json.MarshalIndent(versions, "", "  ")
+			if err != nil {
+				fmt.Println("🚨 Error encoding JSON:", err)
+				exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("%s (%s): revision %d, fetched %s", word.Word, word.ID, word.Revision, formatRefreshTime(word.FetchedAt))
+		if !word.ChangedAt.IsZero() {
+			fmt.Printf(", last changed %s", formatRefreshTime(word.ChangedAt))
+		}
+		fmt.Println()
+
+		if len(versions) == 0 {
+			fmt.Println("No previous versions.")
+			return
+		}
+		fmt.Printf("\n%-8s %-20s %-20s %s\n", "REVISION", "FETCHED", "REPLACED", "HASH")
+		for _, version := range versions {
+			var fetchedAt time.Time
+			if version.Word != nil {
+				fetchedAt = version.Word.FetchedAt
+			}
+			fmt.Printf("%-8d %-20s %-20s %.12s\n", version.Revision,
+				formatRefreshTime(fetchedAt), formatRefreshTime(version.ReplacedAt), version.Hash)
+		}
+	},
+}
+
+// listStaleWords prints the words a refresh would crawl, without crawling them
+func listStaleWords(refreshService *services.RefreshService, options services.RefreshOptions) {
+	candidates, stale, err := refreshService.StaleWords(options)
+	if err != nil {
+		fmt.Println("🚨 Error finding stale words:", err)
+		exit(1)
+	}
+
+	if refreshJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"stale": stale,
+			"words": candidates,
+		}, "", "  ")
+		if err != nil {
+			fmt.Println("🚨 Error encoding JSON:", err)
+			exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("%d words are older than %s; %d would be refreshed (dry run).\n", stale, options.MaxAge, len(candidates))
+	if len(candidates) == 0 {
+		return
+	}
+	fmt.Printf("\n%-30s %-10s %8s %-20s\n", "WORD", "FREQUENCY", "ACCESSES", "FETCHED")
+	for _, candidate := range candidates {
+		fmt.Printf("%-30s %-10s %8d %-20s\n", candidate.ID, candidate.Frequency, candidate.Accesses, formatRefreshTime(candidate.FetchedAt))
+	}
+}
+
+// printRefreshSummary prints the outcome of a refresh run
+func printRefreshSummary(summary *services.RefreshSummary) {
+	if refreshJSON {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			fmt.Println("🚨 Error encoding JSON:", err)
+			exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("\nRefresh complete:\n")
+	fmt.Printf("- Stale words: %d\n", summary.Stale)
+	fmt.Printf("- Refreshed: %d (%d changed, %d unchanged)\n", summary.Refreshed, summary.Changed, summary.Unchanged)
+	fmt.Printf("- Failed: %d\n", summary.Failed)
+	for _, result := range summary.Results {
+		switch {
+		case result.Error != "":
+			fmt.Printf("  ✗ %s: %s\n", result.ID, result.Error)
+		case result.Changed:
+			fmt.Printf("  ✓ %s changed, now revision %d\n", result.ID, result.Revision)
+		}
+	}
+}
+
+// formatRefreshTime formats a fetch or change time, or "never"
+func formatRefreshTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func init() {
+	rootCmd.AddCommand(refreshCmd)
+	refreshCmd.AddCommand(refreshHistoryCmd)
+
+	// Defaults come from REFRESH_MAX_AGE_HOURS, REFRESH_INTERVAL_MINUTES and REFRESH_PRIORITY
+	config := utils.LoadConfig()
+	refreshCmd.Flags().DurationVar(&refreshOlderThan, "older-than", config.RefreshMaxAge, "Refresh words fetched longer ago than this")
+	refreshCmd.Flags().StringVar(&refreshPriority, "priority", config.RefreshPriority, "Refresh the most important words first: frequency or access")
+	refreshCmd.Flags().IntVar(&refreshLimit, "limit", 0, "Maximum number of words per run (0 refreshes all stale words)")
+	refreshCmd.Flags().BoolVar(&refreshDryRun, "dry-run", false, "List the words that would be refreshed without crawling them")
+	refreshCmd.Flags().BoolVar(&refreshDaemon, "daemon", false, "Keep running and refresh stale words every --interval")
+	refreshCmd.Flags().DurationVar(&refreshInterval, "interval", config.RefreshInterval, "Pause between refresh runs with --daemon")
+	refreshCmd.PersistentFlags().BoolVar(&refreshJSON, "json", false, "Print results as JSON")
+}
@@ -4,12 +4,23 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+var (
+	downloadMedia bool
+	mediaDir      string
+	columnList    string
+	templateDir   string
+	templateFile  string
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "goden-crawler",
@@ -19,6 +30,46 @@ detailed linguistic information from the Duden online dictionary.
 
 Built with Golang and Cobra for CLI management, it features a modular 
 and scalable architecture, making it easy to maintain and extend.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Formats provided by plugins
+		container.GetPluginManager()
+
+		// Columns of csv, tsv and xlsx output
+		columns, err := formatter.ParseColumns(columnList)
+		if err != nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+		formatter.SetColumns(columns)
+
+		// Layouts of the markdown, html and template formats
+		if err := formatter.LoadTemplates(templateDir); err != nil {
+			fmt.Println("🚨 Error loading templates:", err)
+			exit(1)
+		}
+		if templateFile != "" {
+			if err := formatter.SetCustomTemplate(templateFile); err != nil {
+				fmt.Println("🚨 Error loading template:", err)
+				exit(1)
+			}
+			useTemplateFormat(cmd)
+		}
+
+		if !downloadMedia {
+			return
+		}
+
+		// Download pronunciation audio and images of every crawled word
+		pipeline, err := container.GetMediaPipeline(mediaDir)
+		if err != nil {
+			fmt.Println("🚨 Error opening media store:", err)
+			exit(1)
+		}
+		container.GetWordService().WithMedia(pipeline)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		flushOutbox()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			cmd.Help()
@@ -27,27 +78,28 @@ and scalable architecture, making it easy to maintain and extend.`,
 	},
 }
 
-// healthCmd represents the health command
-var healthCmd = &cobra.Command{
-	Use:   "health",
-	Short: "Check the health of database connections",
-	Long:  `Checks if the application can connect to all configured databases.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Starting database health check...")
+// flushOutbox applies the writes this command queued before the process exits
+func flushOutbox() {
+	config := utils.LoadConfig()
+	if !container.GetWordRepository().Flush(config.OutboxDrainTimeout) {
+		fmt.Fprintln(os.Stderr, "⚠️  Some database writes are still queued; they are applied by the next run or \"outbox run\".")
+	}
+}
 
-		// Get repository from container
-		repo := container.GetWordRepository()
+// exit flushes the outbox and exits with code. Commands exit through it
+// instead of os.Exit, which would skip PersistentPostRun.
+func exit(code int) {
+	flushOutbox()
+	os.Exit(code)
+}
 
-		// Try to retrieve a test word
-		word, err := repo.GetWord("test")
-		if err != nil {
-			fmt.Println("Database health check failed, but this is expected if no data exists yet.")
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Println("Database health check passed!")
-			fmt.Printf("Retrieved word: %s\n", word.Word)
-		}
-	},
+// useTemplateFormat selects the "template" format for the command's format
+// flag, unless a format was chosen explicitly
+func useTemplateFormat(cmd *cobra.Command) {
+	name, exists := formatFlags[cmd]
+	if exists && !cmd.Flags().Changed(name) {
+		cmd.Flags().Set(name, "template")
+	}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -55,7 +107,7 @@ var healthCmd = &cobra.Command{
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		exit(1)
 	}
 }
 
@@ -68,7 +120,18 @@ func init() {
 	rootCmd.AddCommand(batchCmd)
 	rootCmd.AddCommand(interactiveCmd)
 	rootCmd.AddCommand(completionCmd)
-	rootCmd.AddCommand(healthCmd)
+
+	// Media downloads are optional; defaults come from DOWNLOAD_MEDIA and MEDIA_DIR
+	config := utils.LoadConfig()
+	rootCmd.PersistentFlags().BoolVar(&downloadMedia, "download-media", config.DownloadMedia, "Download pronunciation audio and images into the local media store")
+	rootCmd.PersistentFlags().StringVar(&mediaDir, "media-dir", config.MediaDir, "Directory of the content-addressed media store")
+
+	// Tabular output formats share one column selection
+	rootCmd.PersistentFlags().StringVar(&columnList, "columns", strings.Join(formatter.DefaultColumns, ","), "Columns of csv, tsv and xlsx output (available: "+strings.Join(formatter.ColumnNames(), ", ")+")")
+
+	// Templates of the markdown and html formats can be replaced by files in TEMPLATE_DIR
+	rootCmd.PersistentFlags().StringVar(&templateDir, "template-dir", config.TemplateDir, "Directory with word.md.tmpl and/or word.html.tmpl replacing the built-in layouts")
+	rootCmd.PersistentFlags().StringVar(&templateFile, "template", "", "Render output with a custom Go template file (selects the \"template\" format)")
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
@@ -3,7 +3,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
@@ -11,7 +10,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var format string
+var (
+	format     string
+	allEntries bool
+)
 
 var scrapeCmd = &cobra.Command{
 	Use:   "scrape [word]",
@@ -26,25 +28,54 @@ var scrapeCmd = &cobra.Command{
 		// Get the word service from the container
 		wordService := c.GetWordService()
 
+		// Homonyms: fetch every entry and print them together
+		if allEntries {
+			entries, err := wordService.GetAllEntries(word)
+			if err != nil {
+				fmt.Println("🚨 Error fetching word entries:", err)
+				exit(1)
+			}
+
+			output, err := formatter.FormatEntries(entries, format)
+			if err != nil {
+				fmt.Println("🚨 Error formatting output:", err)
+				exit(1)
+			}
+
+			printOutput(output, format)
+			return
+		}
+
 		// Fetch word data using the service
 		wordData, err := wordService.GetWordData(word)
 		if err != nil {
 			fmt.Println("🚨 Error fetching word data:", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Format output based on user selection
 		output, err := formatter.FormatOutput(wordData, format)
 		if err != nil {
 			fmt.Println("🚨 Error formatting output:", err)
-			os.Exit(1)
+			exit(1)
 		}
 
-		fmt.Println(output)
+		printOutput(output, format)
 	},
 }
 
+// printOutput writes formatted output to stdout. Tabular output is printed
+// as is, so that it can be redirected into a .csv or .xlsx file.
+func printOutput(output, format string) {
+	if formatter.IsTabular(format) {
+		fmt.Print(output)
+		return
+	}
+	fmt.Println(output)
+}
+
 func init() {
 	rootCmd.AddCommand(scrapeCmd)
-	scrapeCmd.Flags().StringVarP(&format, "output", "o", "text", "Output format (text, json)")
+	addFormatFlag(scrapeCmd, &format, "output", "o", "text")
+	scrapeCmd.Flags().BoolVar(&allEntries, "all-entries", false, "Fetch every homonym entry of the word (e.g. all meanings of 'Bank')")
 }
@@ -0,0 +1,137 @@
+// File: cmd/verify.go
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifySource string
+	verifyRepair bool
+	verifyDryRun bool
+	verifyJSON   bool
+)
+
+// verifyCmd checks that all backends hold the same words
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that all databases hold the same words, and repair them",
+	Long: `Enumerates the words in MongoDB, PostgreSQL, Redis and Elasticsearch and
+compares the copies of every entry by content hash against the source backend
+(--source, default mongodb). Entries missing from a backend or differing from
+the source are reported. Redis is a cache, so entries missing there are
+expected and not reported.
+
+With --repair the copy from the source is written to every backend that lacks
+it or differs. Entries that do not exist in the source are reported as
+orphaned and left alone; choose another --source to restore them.
+
+The command exits non-zero if issues remain or a backend could not be read.
+
+Examples:
+  goden-crawler verify
+  goden-crawler verify --source postgres --repair --dry-run
+  goden-crawler verify --json > report.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if verifyJSON {
+			// Keep stdout valid JSON
+			logger.GetGlobalLogger().SetOutput(os.Stderr)
+		}
+
+		repo := container.GetWordRepository()
+		report, err := repo.VerifyConsistency(verifySource, verifyRepair, verifyDryRun)
+		if report == nil {
+			fmt.Println("🚨 Error:", err)
+			exit(1)
+		}
+
+		if verifyJSON {
+			data, jsonErr := json.MarshalIndent(report, "", "  ")
+			if jsonErr != nil {
+				fmt.Println("🚨 Error encoding report:", jsonErr)
+				exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printConsistencyReport(report)
+		}
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "🚨 Error:", err)
+			exit(1)
+		}
+		if !report.Consistent() {
+			exit(1)
+		}
+	},
+}
+
+// printConsistencyReport prints the per-backend summary and one line per issue
+func printConsistencyReport(report *repository.ConsistencyReport) {
+	fmt.Printf("Checked %d entries against %s\n\n", report.Entries, report.Source)
+	fmt.Printf("%-14s %9s %8s %9s %8s\n", "BACKEND", "DOCUMENTS", "MISSING", "DIVERGENT", "REPAIRED")
+	for _, backend := range report.Backends {
+		missing := fmt.Sprint(backend.Missing)
+		if backend.Cache {
+			missing = "-"
+		}
+		line := fmt.Sprintf("%-14s %9d %8s %9d %8d", backend.Backend, backend.Documents, missing, backend.Divergent, backend.Repaired)
+		if backend.Error != "" {
+			line += "  error: " + backend.Error
+		}
+		fmt.Println(line)
+	}
+
+	if len(report.Issues) == 0 {
+		fmt.Println("\n✅ All backends are consistent.")
+		return
+	}
+
+	fmt.Printf("\n%d entries with issues:\n", len(report.Issues))
+	for _, issue := range report.Issues {
+		var parts []string
+		if issue.Orphaned {
+			parts = append(parts, "not in "+report.Source)
+		}
+		if len(issue.Missing) > 0 {
+			parts = append(parts, "missing in "+strings.Join(issue.Missing, ", "))
+		}
+		for _, backend := range issue.Divergent {
+			parts = append(parts, fmt.Sprintf("differs in %s (%.12s, %s has %.12s)",
+				backend, issue.Hashes[backend], report.Source, issue.Hashes[report.Source]))
+		}
+		if len(issue.Repaired) > 0 {
+			parts = append(parts, "repaired in "+strings.Join(issue.Repaired, ", "))
+		}
+		for backend, repairErr := range issue.Errors {
+			parts = append(parts, fmt.Sprintf("repair failed in %s: %s", backend, repairErr))
+		}
+		fmt.Printf("  %-30s %s\n", issue.ID, strings.Join(parts, "; "))
+	}
+
+	if report.DryRun && report.Repair {
+		fmt.Println("\nDry run: no documents were modified.")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVar(&verifySource, "source", "mongodb", "Backend holding the correct copies ("+strings.Join(repository.BackendNames(), ", ")+")")
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false, "Write the source copy to backends that lack it or differ")
+	verifyCmd.Flags().BoolVar(&verifyDryRun, "dry-run", false, "With --repair, report the repairs without writing")
+	verifyCmd.Flags().BoolVar(&verifyJSON, "json", false, "Print the report as JSON")
+	verifyCmd.RegisterFlagCompletionFunc("source", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return repository.BackendNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,167 @@
+// File: cmd/worker.go
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/container"
+	"github.com/amirhossein-jamali/goden-crawler/internal/jobqueue"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workerConcurrency int
+	workerOnce        bool
+	workerVisibility  time.Duration
+	workerMaxAttempts int
+	workerRate        time.Duration
+	workerJitter      time.Duration
+	workerJSON        bool
+	workerDeadLimit   int64
+)
+
+// workerCmd crawls the words of distributed bulk jobs
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Crawl words queued by distributed bulk jobs",
+	Long: `Consumes the words queued with "bulk --distributed" from a Redis stream and
+crawls and stores them. Any number of workers, on any number of hosts, can
+share the queue:
+
+- every word is leased to one worker, which renews the lease while crawling;
+  words of workers that stop renewing are taken over after --visibility
+- failed words are retried with exponential backoff and moved to the dead
+  letters after --max-attempts attempts
+- all workers together send at most one request to Duden per --rate plus up
+  to --jitter, so adding workers does not raise the request rate
+
+Results are stored in the databases; export them with "export".`,
+	Example: `  goden-crawler bulk -i words.txt --distributed
+  goden-crawler worker --concurrency 4
+  goden-crawler worker status`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if workerVisibility < 3*time.Second || workerMaxAttempts < 1 {
+			fmt.Println("🚨 Error: --visibility must be at least 3s and --max-attempts at least 1")
+			exit(1)
+		}
+		queue := newWorkQueue()
+
+		// Share the Duden rate limit with all other workers; media downloads use it as well
+		container.GetDudenScraper().WithLimiter(jobqueue.NewSharedLimiter("duden", workerRate, workerJitter))
+		wordService := container.GetWordService()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		container.WatchInvalidations(ctx)
+
+		worker := jobqueue.NewWorker(queue, workerConcurrency, func(task jobqueue.Task) error {
+			_, err := wordService.GetWordData(task.Word)
+			return err
+		})
+
+		if workerOnce {
+			fmt.Printf("Processing queued words with %d workers until the queue is empty...\n", workerConcurrency)
+		} else {
+			fmt.Printf("Processing queued words with %d workers, press Ctrl+C to stop...\n", workerConcurrency)
+		}
+		worker.Run(ctx, workerOnce)
+
+		processed, failed := worker.Counts()
+		fmt.Printf("\nProcessed %d words (%d failed attempts).\n", processed, failed)
+	},
+}
+
+// workerStatusCmd prints the progress of queued jobs
+var workerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the progress of distributed bulk jobs",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		queue := newWorkQueue()
+
+		stats, err := queue.Stats()
+		if err != nil {
+			fmt.Println("🚨 Error reading work queue:", err)
+			exit(1)
+		}
+		jobs, err := queue.Jobs()
+		if err != nil {
+			fmt.Println("🚨 Error reading jobs:", err)
+			exit(1)
+		}
+		dead, err := queue.DeadTasks(workerDeadLimit)
+		if err != nil {
+			fmt.Println("🚨 Error reading dead letters:", err)
+			exit(1)
+		}
+
+		if workerJSON {
+			data, err := json.MarshalIndent(map[string]interface{}{
+				"queue": stats,
+				"jobs":  jobs,
+				"dead":  dead,
+			}, "", "  ")
+			if err != nil {
+				fmt.Println("🚨 Error encoding JSON:", err)
+				exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		fmt.Printf("Queued: %d, in flight: %d, waiting for retry: %d, dead: %d\n\n",
+			stats.Queued, stats.InFlight, stats.Delayed, stats.Dead)
+
+		if len(jobs) == 0 {
+			fmt.Println("No jobs.")
+		} else {
+			fmt.Printf("%-24s %7s %7s %7s %9s %8s\n", "JOB", "TOTAL", "DONE", "DEAD", "REMAINING", "RETRIED")
+			for _, job := range jobs {
+				fmt.Printf("%-24s %7d %7d %7d %9d %8d\n", job.ID, job.Total, job.Done, job.Dead, job.Remaining(), job.Retried)
+			}
+		}
+
+		if len(dead) > 0 {
+			fmt.Printf("\nDead letters:\n")
+			for _, task := range dead {
+				fmt.Printf("  %-30s job %s, %d attempts: %s\n", task.Word, task.Job, task.Attempts, task.Error)
+			}
+		}
+	},
+}
+
+// newWorkQueue creates the work queue of this process with the flag settings
+func newWorkQueue() *jobqueue.Queue {
+	hostname, _ := os.Hostname()
+	options := jobqueue.DefaultOptions()
+	options.Visibility = workerVisibility
+	options.MaxAttempts = workerMaxAttempts
+	options.JobRetention = utils.LoadConfig().QueueJobRetention
+	return jobqueue.NewQueue(fmt.Sprintf("%s-%d", hostname, os.Getpid()), options)
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+	workerCmd.AddCommand(workerStatusCmd)
+
+	// Defaults come from QUEUE_VISIBILITY_SECONDS, QUEUE_MAX_ATTEMPTS, QUEUE_RATE_MS and QUEUE_RATE_JITTER_MS
+	config := utils.LoadConfig()
+	workerCmd.PersistentFlags().DurationVar(&workerVisibility, "visibility", config.QueueVisibility, "Time a word stays leased without renewal before another worker takes it over")
+	workerCmd.PersistentFlags().IntVar(&workerMaxAttempts, "max-attempts", config.QueueMaxAttempts, "Attempts per word before it is moved to the dead letters")
+	workerCmd.Flags().IntVarP(&workerConcurrency, "concurrency", "c", 2, "Number of words crawled concurrently by this worker")
+	workerCmd.Flags().BoolVar(&workerOnce, "once", false, "Exit when the queue is empty instead of waiting for new jobs")
+	workerCmd.Flags().DurationVar(&workerRate, "rate", config.QueueRateInterval, "Minimum interval between Duden requests of all workers together")
+	workerCmd.Flags().DurationVar(&workerJitter, "jitter", config.QueueRateJitter, "Random delay added to the request interval")
+
+	workerStatusCmd.Flags().BoolVar(&workerJSON, "json", false, "Print the status as JSON")
+	workerStatusCmd.Flags().Int64Var(&workerDeadLimit, "dead", 20, "Number of dead-lettered words to list")
+}
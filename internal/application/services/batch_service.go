@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/amirhossein-jamali/goden-crawler/internal/lemmatizer"
 	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
@@ -41,6 +42,12 @@ func NewBatchService(wordService *WordService, repository *repository.WordReposi
 func (s *BatchService) ProcessWords(ctx context.Context, words []string) []BatchResult {
 	logger.Info("Starting batch processing", logger.F("word_count", len(words)), logger.F("workers", s.workers))
 
+	// Read all cached words in one round trip instead of one per word
+	cached := s.repository.GetCachedWords(words)
+	if len(cached) > 0 {
+		logger.Info("Words found in cache", logger.F("cached", len(cached)))
+	}
+
 	// Create channels for words and results
 	wordChan := make(chan string, len(words))
 	resultChan := make(chan BatchResult, len(words))
@@ -49,7 +56,7 @@ func (s *BatchService) ProcessWords(ctx context.Context, words []string) []Batch
 	var wg sync.WaitGroup
 	for i := 0; i < s.workers; i++ {
 		wg.Add(1)
-		go s.worker(ctx, &wg, wordChan, resultChan)
+		go s.worker(ctx, &wg, wordChan, resultChan, cached)
 	}
 
 	// Send words to the channel
@@ -73,26 +80,21 @@ func (s *BatchService) ProcessWords(ctx context.Context, words []string) []Batch
 }
 
 // worker processes words from the channel
-func (s *BatchService) worker(ctx context.Context, wg *sync.WaitGroup, wordChan <-chan string, resultChan chan<- BatchResult) {
+func (s *BatchService) worker(ctx context.Context, wg *sync.WaitGroup, wordChan <-chan string, resultChan chan<- BatchResult, cached map[string]*models.Word) {
 	defer wg.Done()
 
 	for word := range wordChan {
+		// Cached words are already stored everywhere
+		if data := cached[word]; data != nil {
+			resultChan <- BatchResult{Word: word, Data: withLookup(data, word, lemmatizer.MethodExact)}
+			continue
+		}
+
 		// Create a context with timeout
 		tctx, cancel := context.WithTimeout(ctx, s.timeout)
 
-		// Process the word
-		result := s.processWord(tctx, word)
-		resultChan <- result
-
-		// Save the result to the repository if successful
-		if result.Error == nil && result.Data != nil {
-			err := s.repository.SaveWord(result.Data)
-			if err != nil {
-				logger.Error("Failed to save word to repository",
-					logger.F("word", word),
-					logger.F("error", err))
-			}
-		}
+		// Process the word; the word service saves crawled words
+		resultChan <- s.processWord(tctx, word)
 
 		cancel()
 	}
@@ -107,20 +109,8 @@ func (s *BatchService) processWord(ctx context.Context, word string) BatchResult
 
 	// Process the word in a goroutine
 	go func() {
-		// First check if the word already exists in the repository
-		data, err := s.repository.GetWord(word)
-		if err == nil && data != nil {
-			logger.Info("Word found in repository", logger.F("word", word))
-			resultChan <- BatchResult{
-				Word:  word,
-				Data:  data,
-				Error: nil,
-			}
-			return
-		}
-
-		// If not found, get it from the word service
-		data, err = s.wordService.GetWordData(word)
+		// The word service reads stored words before crawling
+		data, err := s.wordService.GetWordData(word)
 		resultChan <- BatchResult{
 			Word:  word,
 			Data:  data,
@@ -0,0 +1,302 @@
+// File: internal/application/services/refresh_service.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/crawler"
+	"github.com/amirhossein-jamali/goden-crawler/internal/media"
+	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
+)
+
+// Refresh priorities decide which stale words are crawled first
+const (
+	PriorityFrequency = "frequency" // most frequent words according to Duden
+	PriorityAccess    = "access"    // most read words
+)
+
+// frequencyRanks orders Duden's frequency ratings; unrated words come last
+var frequencyRanks = map[string]int{
+	"very_high": 5,
+	"high":      4,
+	"medium":    3,
+	"low":       2,
+	"very_low":  1,
+}
+
+// RefreshOptions selects the words a refresh run crawls again
+type RefreshOptions struct {
+	MaxAge   time.Duration // words fetched longer ago are stale
+	Priority string        // PriorityFrequency or PriorityAccess
+	Limit    int           // maximum number of words per run; 0 refreshes all stale words
+}
+
+// RefreshCandidate is a stale word due for refreshing
+type RefreshCandidate struct {
+	ID        string    `json:"id"`
+	Word      string    `json:"word"`
+	Frequency string    `json:"frequency,omitempty"`
+	Accesses  int64     `json:"accesses"`
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+}
+
+// RefreshResult is the outcome of refreshing one word
+type RefreshResult struct {
+	ID       string `json:"id"`
+	Word     string `json:"word"`
+	Changed  bool   `json:"changed"`
+	Revision int    `json:"revision"`
+	Error    string `json:"error,omitempty"`
+}
+
+// RefreshSummary counts the outcomes of a refresh run
+type RefreshSummary struct {
+	Stale     int             `json:"stale"`
+	Refreshed int             `json:"refreshed"`
+	Changed   int             `json:"changed"`
+	Unchanged int             `json:"unchanged"`
+	Failed    int             `json:"failed"`
+	Results   []RefreshResult `json:"results"`
+}
+
+// RefreshService crawls stored words again once they are older than a maximum
+// age. When the content changed, the previous version is kept and the revision
+// of the word is increased.
+type RefreshService struct {
+	crawler    crawler.DudenCrawler
+	repository *repository.WordRepository
+	media      *media.Pipeline
+}
+
+// NewRefreshService creates a RefreshService. The crawler must not answer
+// from a cache, or refreshed words would not change.
+func NewRefreshService(crawler crawler.DudenCrawler, repository *repository.WordRepository) *RefreshService {
+	return &RefreshService{
+		crawler:    crawler,
+		repository: repository,
+	}
+}
+
+// WithMedia enables downloading media that refreshed words link to for the first time
+func (s *RefreshService) WithMedia(pipeline *media.Pipeline) *RefreshService {
+	s.media = pipeline
+	return s
+}
+
+// StaleWords lists the stored words fetched before the maximum age, or never,
+// in the order they are refreshed: by priority, then oldest first. The list
+// is cut to the limit of the options; the number of all stale words is
+// returned as well.
+func (s *RefreshService) StaleWords(options RefreshOptions) ([]RefreshCandidate, int, error) {
+	if options.Priority != PriorityFrequency && options.Priority != PriorityAccess {
+		return nil, 0, fmt.Errorf("unknown refresh priority %q (available: %s, %s)", options.Priority, PriorityFrequency, PriorityAccess)
+	}
+	cutoff := time.Now().Add(-options.MaxAge)
+
+	var candidates []RefreshCandidate
+	err := s.repository.ForEachWord(func(word *models.Word) error {
+		if !word.FetchedAt.IsZero() && word.FetchedAt.After(cutoff) {
+			return nil
+		}
+		word.EnsureIdentity()
+		candidates = append(candidates, RefreshCandidate{
+			ID:        word.ID,
+			Word:      word.Word,
+			Frequency: word.Frequency,
+			FetchedAt: word.FetchedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if options.Priority == PriorityAccess {
+		ids := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			ids[i] = candidate.ID
+		}
+		// Without counts all words are equally popular and the oldest go first
+		counts, err := s.repository.AccessCounts(ids)
+		if err != nil {
+			logger.Warn("Failed to read access counts, refreshing oldest words first", logger.F("error", err))
+		}
+		for i := range candidates {
+			candidates[i].Accesses = counts[candidates[i].ID]
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if options.Priority == PriorityAccess && a.Accesses != b.Accesses {
+			return a.Accesses > b.Accesses
+		}
+		if options.Priority == PriorityFrequency && frequencyRanks[a.Frequency] != frequencyRanks[b.Frequency] {
+			return frequencyRanks[a.Frequency] > frequencyRanks[b.Frequency]
+		}
+		return a.FetchedAt.Before(b.FetchedAt)
+	})
+
+	stale := len(candidates)
+	if options.Limit > 0 && len(candidates) > options.Limit {
+		candidates = candidates[:options.Limit]
+	}
+	return candidates, stale, nil
+}
+
+// Refresh crawls the stale words again until all are done or ctx is done
+func (s *RefreshService) Refresh(ctx context.Context, options RefreshOptions) (*RefreshSummary, error) {
+	candidates, stale, err := s.StaleWords(options)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Refreshing stale words",
+		logger.F("stale", stale),
+		logger.F("selected", len(candidates)),
+		logger.F("max_age", options.MaxAge.String()),
+		logger.F("priority", options.Priority))
+
+	summary := &RefreshSummary{Stale: stale, Results: []RefreshResult{}}
+	for _, candidate := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		result := s.RefreshWord(candidate.ID)
+		summary.Results = append(summary.Results, result)
+		switch {
+		case result.Error != "":
+			summary.Failed++
+		case result.Changed:
+			summary.Refreshed++
+			summary.Changed++
+		default:
+			summary.Refreshed++
+			summary.Unchanged++
+		}
+	}
+
+	logger.Info("Refresh finished",
+		logger.F("refreshed", summary.Refreshed),
+		logger.F("changed", summary.Changed),
+		logger.F("failed", summary.Failed))
+	return summary, ctx.Err()
+}
+
+// RefreshWord crawls a stored entry again and saves it. If the dictionary
+// content changed, the stored version is kept first; if that fails, the entry
+// is left as it is.
+func (s *RefreshService) RefreshWord(id string) RefreshResult {
+	result := RefreshResult{ID: id}
+	fail := func(err error) RefreshResult {
+		logger.Error("Failed to refresh word", logger.F("id", id), logger.F("error", err))
+		result.Error = err.Error()
+		return result
+	}
+
+	stored, err := s.repository.GetStoredWord(id)
+	if err != nil {
+		return fail(err)
+	}
+	stored.EnsureIdentity()
+	result.Word = stored.Word
+
+	fresh, err := s.crawler.FetchEntry(utils.ToDudenSlug(stored.ID))
+	if err != nil {
+		return fail(err)
+	}
+	// Keep the identity of the stored entry, e.g. of words stored before entries had IDs
+	fresh.ID = stored.ID
+	fresh.EnsureIdentity()
+
+	oldHash, err := models.DictionaryHash(stored)
+	if err != nil {
+		return fail(err)
+	}
+	newHash, err := models.DictionaryHash(fresh)
+	if err != nil {
+		return fail(err)
+	}
+
+	carryOverMedia(stored, fresh)
+	if s.media != nil {
+		s.media.Process(fresh)
+	}
+
+	fresh.Revision = stored.Revision
+	fresh.ChangedAt = stored.ChangedAt
+	if oldHash != newHash {
+		version := &models.WordVersion{
+			WordID:     stored.ID,
+			Revision:   stored.Revision,
+			Hash:       oldHash,
+			ReplacedAt: fresh.FetchedAt,
+			Word:       stored,
+		}
+		if err := s.repository.SaveWordVersion(version); err != nil {
+			return fail(fmt.Errorf("keeping previous version: %w", err))
+		}
+		fresh.Revision++
+		fresh.ChangedAt = fresh.FetchedAt
+		result.Changed = true
+	}
+	result.Revision = fresh.Revision
+
+	if err := s.repository.SaveWord(fresh); err != nil {
+		return fail(err)
+	}
+
+	logger.Info("Refreshed word",
+		logger.F("word", fresh.Word),
+		logger.F("changed", result.Changed),
+		logger.F("revision", fresh.Revision))
+	return result
+}
+
+// carryOverMedia copies the downloaded files of the stored word to the fresh
+// copy wherever it still links to the same audio or image
+func carryOverMedia(stored, fresh *models.Word) {
+	files := make(map[string]*models.MediaFile)
+	for _, pronunciation := range stored.Pronunciation {
+		if pronunciation.Audio != "" && pronunciation.AudioFile != nil {
+			files[pronunciation.Audio] = pronunciation.AudioFile
+		}
+	}
+	collectImages(stored.Meanings, files)
+
+	for i := range fresh.Pronunciation {
+		pronunciation := &fresh.Pronunciation[i]
+		if file, exists := files[pronunciation.Audio]; exists && pronunciation.AudioFile == nil {
+			pronunciation.AudioFile = file
+		}
+	}
+	applyImages(fresh.Meanings, files)
+}
+
+// collectImages maps the image links of meanings and their sub-meanings to their files
+func collectImages(meanings []models.Meaning, files map[string]*models.MediaFile) {
+	for _, meaning := range meanings {
+		if meaning.Image != "" && meaning.ImageFile != nil {
+			files[meaning.Image] = meaning.ImageFile
+		}
+		collectImages(meaning.SubMeanings, files)
+	}
+}
+
+// applyImages sets the files of image links found by collectImages
+func applyImages(meanings []models.Meaning, files map[string]*models.MediaFile) {
+	for i := range meanings {
+		meaning := &meanings[i]
+		if file, exists := files[meaning.Image]; exists && meaning.ImageFile == nil {
+			meaning.ImageFile = file
+		}
+		applyImages(meaning.SubMeanings, files)
+	}
+}
@@ -3,16 +3,27 @@
 package services
 
 import (
+	"sync"
+
 	"github.com/amirhossein-jamali/goden-crawler/internal/crawler"
+	"github.com/amirhossein-jamali/goden-crawler/internal/lemmatizer"
+	"github.com/amirhossein-jamali/goden-crawler/internal/media"
 	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
 )
 
+// maxRuleCandidates limits how many suffix-stripping guesses are fetched per lookup
+const maxRuleCandidates = 4
+
 // WordService provides operations for word data
 type WordService struct {
 	crawler    crawler.DudenCrawler
 	repository *repository.WordRepository
+	lemmatizer *lemmatizer.Lemmatizer
+	lemmaOnce  sync.Once
+	media      *media.Pipeline
 }
 
 // NewWordService creates a new WordService
@@ -20,10 +31,19 @@ func NewWordService(crawler crawler.DudenCrawler, repository *repository.WordRep
 	return &WordService{
 		crawler:    crawler,
 		repository: repository,
+		lemmatizer: lemmatizer.NewLemmatizer(),
 	}
 }
 
-// GetWordData retrieves word data from either the repository or by crawling
+// WithMedia enables downloading pronunciation audio and images of crawled words
+func (s *WordService) WithMedia(pipeline *media.Pipeline) *WordService {
+	s.media = pipeline
+	return s
+}
+
+// GetWordData retrieves word data from either the repository or by crawling.
+// Inflected forms ("Häuser", "ging") are mapped to their lemma before fetching;
+// the result records both the original form and the lemma.
 func (s *WordService) GetWordData(word string) (*models.Word, error) {
 	logger.Info("Getting word data", logger.F("word", word))
 
@@ -31,10 +51,41 @@ func (s *WordService) GetWordData(word string) (*models.Word, error) {
 	wordData, err := s.repository.GetWord(word)
 	if err == nil {
 		logger.Info("Word found in repository", logger.F("word", word))
-		return wordData, nil
+		s.repository.RecordAccess(wordData)
+		return withLookup(wordData, word, lemmatizer.MethodExact), nil
+	}
+
+	// Try the lemma candidates, most reliable first
+	s.loadLemmas()
+	ruleAttempts := 0
+	for _, candidate := range s.lemmatizer.Candidates(word) {
+		if candidate.Method == lemmatizer.MethodRule {
+			if ruleAttempts == maxRuleCandidates {
+				break
+			}
+			ruleAttempts++
+		}
+
+		if candidate.Lemma != word {
+			if wordData, err := s.repository.GetWord(candidate.Lemma); err == nil {
+				logger.Info("Lemma found in repository", logger.F("word", word), logger.F("lemma", candidate.Lemma))
+				s.repository.RecordAccess(wordData)
+				return withLookup(wordData, word, candidate.Method), nil
+			}
+		}
+
+		wordData, err := s.crawler.FetchEntry(utils.ToDudenSlug(candidate.Lemma))
+		if err != nil {
+			logger.Debug("Lemma candidate not found", logger.F("word", word), logger.F("lemma", candidate.Lemma))
+			continue
+		}
+
+		logger.Info("Resolved word to lemma", logger.F("word", word), logger.F("lemma", wordData.Word), logger.F("method", candidate.Method))
+		s.store(wordData)
+		return withLookup(wordData, word, candidate.Method), nil
 	}
 
-	// If not found in repository, crawl the data
+	// Fall back to Duden's search results
 	logger.Info("Word not found in repository, crawling", logger.F("word", word))
 	wordData, err = s.crawler.FetchWordDataStructured(word)
 	if err != nil {
@@ -42,16 +93,93 @@ func (s *WordService) GetWordData(word string) (*models.Word, error) {
 		return nil, err
 	}
 
-	// Save the word data to the repository
-	err = s.repository.SaveWord(wordData)
+	s.store(wordData)
+	return withLookup(wordData, word, lemmatizer.MethodSearch), nil
+}
+
+// GetStoredWord retrieves a word from the repository only, without crawling
+func (s *WordService) GetStoredWord(word string) (*models.Word, error) {
+	wordData, err := s.repository.GetWord(word)
 	if err != nil {
-		logger.Error("Failed to save word to repository", logger.F("word", word), logger.F("error", err))
-		// Continue even if saving fails
+		return nil, err
+	}
+	s.repository.RecordAccess(wordData)
+	return withLookup(wordData, word, lemmatizer.MethodExact), nil
+}
+
+// store saves a crawled word, downloads its media if enabled and learns its inflected forms
+func (s *WordService) store(wordData *models.Word) {
+	s.lemmatizer.AddWord(wordData)
+
+	if s.media != nil {
+		s.media.Process(wordData)
+	}
+
+	// Continue even if saving fails
+	if err := s.repository.SaveWord(wordData); err != nil {
+		logger.Error("Failed to save word to repository", logger.F("word", wordData.Word), logger.F("error", err))
 	}
+}
 
+// loadLemmas builds the form index from stored words on first use
+func (s *WordService) loadLemmas() {
+	s.lemmaOnce.Do(func() {
+		if err := s.lemmatizer.Load(s.repository.ForEachWord); err != nil {
+			logger.Warn("Failed to load inflected forms", logger.F("error", err))
+		}
+		logger.Info("Loaded inflected forms", logger.F("forms", s.lemmatizer.Size()))
+	})
+}
+
+// withLookup returns a copy of a word annotated with how the input was resolved.
+// The copy keeps cached and stored words free of per-request data.
+func withLookup(wordData *models.Word, form, method string) *models.Word {
+	result := *wordData
+	result.Lookup = &models.Lookup{
+		Form:   form,
+		Lemma:  wordData.Word,
+		Method: method,
+	}
+	return &result
+}
+
+// GetWordEntries lists the dictionary entries (homonyms) available for a word
+func (s *WordService) GetWordEntries(word string) ([]models.Entry, error) {
+	logger.Info("Discovering word entries", logger.F("word", word))
+	return s.crawler.DiscoverEntries(word)
+}
+
+// GetEntryData crawls a single dictionary entry by its Duden URL slug
+func (s *WordService) GetEntryData(slug string) (*models.Word, error) {
+	logger.Info("Getting entry data", logger.F("slug", slug))
+
+	wordData, err := s.crawler.FetchEntry(slug)
+	if err != nil {
+		logger.Error("Failed to fetch entry", logger.F("slug", slug), logger.F("error", err))
+		return nil, err
+	}
+
+	s.store(wordData)
 	return wordData, nil
 }
 
+// GetAllEntries crawls every entry for a word, keyed by Duden URL slug
+func (s *WordService) GetAllEntries(word string) (map[string]*models.Word, error) {
+	logger.Info("Getting all entries", logger.F("word", word))
+
+	entries, err := s.crawler.FetchAllEntries(word)
+	if err != nil {
+		logger.Error("Failed to fetch entries", logger.F("word", word), logger.F("error", err))
+		return nil, err
+	}
+
+	for _, wordData := range entries {
+		s.store(wordData)
+	}
+
+	return entries, nil
+}
+
 // GetWordSuggestions retrieves word suggestions
 func (s *WordService) GetWordSuggestions(word string) ([]models.Synonym, error) {
 	logger.Info("Getting word suggestions", logger.F("word", word))
@@ -72,6 +200,12 @@ func (s *WordService) GetWordSuggestions(word string) ([]models.Synonym, error)
 	return s.crawler.GetSuggestions(word)
 }
 
+// GetWordsByOrigin retrieves stored words whose etymology mentions the given language
+func (s *WordService) GetWordsByOrigin(language string) ([]models.Word, error) {
+	logger.Info("Getting words by origin", logger.F("language", language))
+	return s.repository.SearchByOriginLanguage(language)
+}
+
 // GetAvailableSections returns all available sections
 func (s *WordService) GetAvailableSections() []string {
 	return s.crawler.GetAvailableSections()
@@ -3,6 +3,8 @@
 package crawler
 
 import (
+	"fmt"
+
 	"github.com/PuerkitoBio/goquery"
 	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/cache"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
@@ -30,7 +32,7 @@ func (s *CachedDudenScraper) makeRequest(url string) (*goquery.Document, error)
 }
 
 // fetchWordDoc delegates to the underlying scraper's fetchWordDoc method
-func (s *CachedDudenScraper) fetchWordDoc(word string) (*goquery.Document, error) {
+func (s *CachedDudenScraper) fetchWordDoc(word string) (*goquery.Document, string, error) {
 	// No caching for HTML documents
 	return s.scraper.fetchWordDoc(word)
 }
@@ -66,6 +68,56 @@ func (s *CachedDudenScraper) FetchWordDataStructured(word string) (*models.Word,
 	return data, nil
 }
 
+// DiscoverEntries lists all dictionary entries for a lemma
+func (s *CachedDudenScraper) DiscoverEntries(word string) ([]models.Entry, error) {
+	// No caching for entry lists as they might change
+	return s.scraper.DiscoverEntries(word)
+}
+
+// FetchEntry fetches a single dictionary entry, using the slug as cache key
+func (s *CachedDudenScraper) FetchEntry(slug string) (*models.Word, error) {
+	if s.cache != nil {
+		if cachedData, found := s.cache.Get(slug); found {
+			logger.Info("Using cached data for entry", logger.F("slug", slug))
+			return cachedData, nil
+		}
+	}
+
+	logger.Info("Fetching entry from source", logger.F("slug", slug))
+	data, err := s.scraper.FetchEntry(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cache != nil {
+		s.cache.Set(slug, data)
+	}
+	return data, nil
+}
+
+// FetchAllEntries fetches every entry for a lemma, reusing cached entries
+func (s *CachedDudenScraper) FetchAllEntries(word string) (map[string]*models.Word, error) {
+	entries, err := s.scraper.DiscoverEntries(word)
+	if err != nil || len(entries) == 0 {
+		return s.scraper.FetchAllEntries(word)
+	}
+
+	result := make(map[string]*models.Word, len(entries))
+	for _, entry := range entries {
+		data, err := s.FetchEntry(entry.Slug)
+		if err != nil {
+			logger.Warn("Failed to fetch entry", logger.F("slug", entry.Slug), logger.F("error", err))
+			continue
+		}
+		result[entry.Slug] = data
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("failed to fetch any entry for '%s'", word)
+	}
+	return result, nil
+}
+
 // GetSuggestions returns a list of suggested words for a given input
 func (s *CachedDudenScraper) GetSuggestions(word string) ([]models.Synonym, error) {
 	// No caching for suggestions as they might change
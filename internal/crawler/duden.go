@@ -16,6 +16,16 @@ type DudenCrawler interface {
 	// This is the primary method for getting complete, structured linguistic data.
 	FetchWordDataStructured(word string) (*models.Word, error)
 
+	// DiscoverEntries lists all dictionary entries (homonyms) for a lemma.
+	// Each entry is identified by its Duden URL slug, e.g. "Bank_Sitzgelegenheit".
+	DiscoverEntries(word string) ([]models.Entry, error)
+
+	// FetchEntry fetches a single dictionary entry by its Duden URL slug
+	FetchEntry(slug string) (*models.Word, error)
+
+	// FetchAllEntries fetches every entry for a lemma, keyed by Duden URL slug
+	FetchAllEntries(word string) (map[string]*models.Word, error)
+
 	// GetSuggestions returns a list of suggested words for a given input
 	// Useful when the exact word is not found but similar words exist.
 	GetSuggestions(word string) ([]models.Synonym, error)
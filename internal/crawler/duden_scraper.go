@@ -14,36 +14,70 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/amirhossein-jamali/goden-crawler/internal/crawler/extractors"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
 )
 
 // DudenScraper scrapes data from the Duden website
 // It implements the DudenCrawler interface
 type DudenScraper struct {
 	client           *http.Client
+	limiter          utils.Limiter
 	extractorFactory *extractors.ExtractorFactory
 	baseURL          string
 	searchURL        string
 	headers          map[string]string
+	retries          int
 }
 
-// NewDudenScraper creates a new DudenScraper
+// NewDudenScraper creates a new DudenScraper.
+// All requests, including those made by extractors, share one rate limiter.
 func NewDudenScraper() *DudenScraper {
-	return &DudenScraper{
+	s := &DudenScraper{
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		limiter:          utils.NewRateLimiter(500*time.Millisecond, 500*time.Millisecond),
 		extractorFactory: extractors.NewExtractorFactory(),
 		baseURL:          "https://www.duden.de",
 		searchURL:        "https://www.duden.de/suchen/dudenonline/",
 		headers: map[string]string{
 			"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
 		},
+		retries: 3,
 	}
+	s.extractorFactory.SetFetcher(s)
+	return s
+}
+
+// WithRateLimit sets the minimum interval and random jitter between requests
+func (s *DudenScraper) WithRateLimit(interval, jitter time.Duration) *DudenScraper {
+	s.limiter = utils.NewRateLimiter(interval, jitter)
+	return s
+}
+
+// WithLimiter replaces the rate limiter, e.g. by one shared with other processes
+func (s *DudenScraper) WithLimiter(limiter utils.Limiter) *DudenScraper {
+	s.limiter = limiter
+	return s
+}
+
+// RateLimiter returns the limiter shared by all requests of this scraper
+func (s *DudenScraper) RateLimiter() utils.Limiter {
+	return s.limiter
+}
+
+// FetchDocument fetches a Duden page through the shared, rate-limited client.
+// Relative links such as "/synonyme/Haus" are resolved against the Duden base URL.
+func (s *DudenScraper) FetchDocument(link string) (*goquery.Document, error) {
+	if strings.HasPrefix(link, "/") {
+		link = s.baseURL + link
+	}
+	return s.makeRequest(link)
 }
 
 // FetchWordData fetches data for a word
 func (s *DudenScraper) FetchWordData(word string) (map[string]string, error) {
-	doc, err := s.fetchWordDoc(word)
+	doc, _, err := s.fetchWordDoc(word)
 	if err != nil {
 		return nil, err
 	}
@@ -72,13 +106,106 @@ func (s *DudenScraper) FetchWordData(word string) (map[string]string, error) {
 
 // FetchWordDataStructured fetches data for a word and returns a structured Word object
 func (s *DudenScraper) FetchWordDataStructured(word string) (*models.Word, error) {
-	doc, err := s.fetchWordDoc(word)
+	doc, pageURL, err := s.fetchWordDoc(word)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.parseWordDoc(doc, pageURL), nil
+}
+
+// DiscoverEntries lists all dictionary entries for a lemma from the search page.
+// Homonyms such as "Bank" or "Schloss" have one entry per meaning group, each
+// with its own slug; entries for other lemmas in the results are skipped.
+func (s *DudenScraper) DiscoverEntries(word string) ([]models.Entry, error) {
+	searchURL := fmt.Sprintf("%s%s", s.searchURL, url.QueryEscape(word))
+
+	doc, err := s.makeRequest(searchURL)
+	if err != nil {
+		return nil, err
+	}
+
+	lemma := utils.NormalizeGerman(word)
+	seen := make(map[string]bool)
+	var entries []models.Entry
+
+	doc.Find("a[href^='/rechtschreibung/']").Each(func(i int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		slug := entrySlug(href)
+		if slug == "" || seen[slug] || utils.NormalizeGerman(slugLemma(slug)) != lemma {
+			return
+		}
+		seen[slug] = true
+
+		entries = append(entries, models.Entry{
+			Slug:  slug,
+			Title: strings.Join(strings.Fields(sel.Text()), " "),
+			Link:  s.entryURL(slug),
+		})
+	})
+
+	return entries, nil
+}
+
+// FetchEntry fetches a single dictionary entry by its Duden URL slug
+func (s *DudenScraper) FetchEntry(slug string) (*models.Word, error) {
+	pageURL := s.entryURL(slug)
+	doc, err := s.makeRequest(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(doc.Find("title").Text(), "Fehlermeldung") {
+		return nil, fmt.Errorf("entry '%s' not found", slug)
+	}
+
+	return s.parseWordDoc(doc, pageURL), nil
+}
+
+// FetchAllEntries fetches every entry for a lemma, keyed by Duden URL slug.
+// A word without homonyms yields a single entry.
+func (s *DudenScraper) FetchAllEntries(word string) (map[string]*models.Word, error) {
+	entries, err := s.DiscoverEntries(word)
+	if err != nil || len(entries) == 0 {
+		doc, pageURL, err := s.fetchWordDoc(word)
+		if err != nil {
+			return nil, err
+		}
+
+		wordData := s.parseWordDoc(doc, pageURL)
+		return map[string]*models.Word{wordData.ID: wordData}, nil
+	}
+
+	result := make(map[string]*models.Word, len(entries))
+	for _, entry := range entries {
+		wordData, err := s.FetchEntry(entry.Slug)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to fetch entry '%s': %v\n", entry.Slug, err)
+			continue
+		}
+		result[entry.Slug] = wordData
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("failed to fetch any entry for '%s'", word)
+	}
+
+	return result, nil
+}
+
+// parseWordDoc extracts a structured Word object from an entry page.
+// The entry ID is the slug from the canonical link, falling back to the page URL.
+func (s *DudenScraper) parseWordDoc(doc *goquery.Document, pageURL string) *models.Word {
 	// Create a Word object
-	wordData := &models.Word{}
+	wordData := &models.Word{
+		ID:        canonicalSlug(doc),
+		SourceURL: pageURL,
+		FetchedAt: time.Now().UTC(),
+	}
+	if wordData.ID == "" {
+		wordData.ID = entrySlug(pageURL)
+	} else {
+		wordData.SourceURL = s.entryURL(wordData.ID)
+	}
 
 	// Extract general info
 	generalInfoExtractor, _ := s.extractorFactory.CreateExtractor("general_info", doc)
@@ -109,6 +236,9 @@ func (s *DudenScraper) FetchWordDataStructured(word string) (*models.Word, error
 		if synonyms, ok := synonymData["synonyms"].([]models.Synonym); ok {
 			wordData.Synonyms = synonyms
 		}
+		if groups, ok := synonymData["groups"].([]models.SynonymGroup); ok {
+			wordData.SynonymGroups = groups
+		}
 	}
 
 	// Extract grammar
@@ -119,12 +249,36 @@ func (s *DudenScraper) FetchWordDataStructured(word string) (*models.Word, error
 				wordData.Grammar = text
 			}
 		}
+		if forms, ok := grammarData["forms"].([]string); ok {
+			wordData.InflectedForms = forms
+		}
 	}
 
-	// Extract origin
+	// Extract etymology
 	originExtractor, _ := s.extractorFactory.CreateExtractor("herkunft", doc)
-	if origins, ok := originExtractor.Extract().([]models.Origin); ok {
-		wordData.Origin = origins
+	if etymology, ok := originExtractor.Extract().(*models.Etymology); ok {
+		wordData.Etymology = etymology
+	}
+
+	// Extract related words
+	antonymExtractor, _ := s.extractorFactory.CreateExtractor("gegenwort", doc)
+	if antonyms, ok := antonymExtractor.Extract().([]models.RelatedWord); ok {
+		wordData.Antonyms = antonyms
+	}
+
+	collocationsExtractor, _ := s.extractorFactory.CreateExtractor("typische_verbindungen", doc)
+	if collocations, ok := collocationsExtractor.Extract().([]models.Collocation); ok {
+		wordData.Collocations = collocations
+	}
+
+	compoundExtractor, _ := s.extractorFactory.CreateExtractor("zusammensetzungen", doc)
+	if compounds, ok := compoundExtractor.Extract().([]models.RelatedWord); ok {
+		wordData.Compounds = compounds
+	}
+
+	wordFamilyExtractor, _ := s.extractorFactory.CreateExtractor("wortfamilie", doc)
+	if family, ok := wordFamilyExtractor.Extract().([]models.RelatedWord); ok {
+		wordData.WordFamily = family
 	}
 
 	// Extract fun facts
@@ -133,7 +287,9 @@ func (s *DudenScraper) FetchWordDataStructured(word string) (*models.Word, error
 		wordData.FunFacts = funFacts
 	}
 
-	return wordData, nil
+	// Pages outside /rechtschreibung/ have no slug; fall back to the display word
+	wordData.EnsureIdentity()
+	return wordData
 }
 
 // GetSuggestions gets suggestions for a word
@@ -166,8 +322,46 @@ func (s *DudenScraper) GetAvailableSections() []string {
 	return s.extractorFactory.GetAvailableSections()
 }
 
-// fetchWordDoc fetches the HTML document for a word
-func (s *DudenScraper) fetchWordDoc(word string) (*goquery.Document, error) {
+// entryURL returns the page URL of an entry slug
+func (s *DudenScraper) entryURL(slug string) string {
+	return fmt.Sprintf("%s/rechtschreibung/%s", s.baseURL, slug)
+}
+
+// entrySlug extracts the slug from an entry link such as "/rechtschreibung/Bank_Sitzgelegenheit"
+func entrySlug(href string) string {
+	if idx := strings.IndexAny(href, "?#"); idx >= 0 {
+		href = href[:idx]
+	}
+	idx := strings.LastIndex(href, "/rechtschreibung/")
+	if idx < 0 {
+		return ""
+	}
+	slug := strings.Trim(href[idx+len("/rechtschreibung/"):], "/")
+	if unescaped, err := url.PathUnescape(slug); err == nil {
+		slug = unescaped
+	}
+	return slug
+}
+
+// slugLemma returns the lemma part of a slug; "Bank_Sitzgelegenheit" yields "Bank"
+func slugLemma(slug string) string {
+	if idx := strings.Index(slug, "_"); idx > 0 {
+		return slug[:idx]
+	}
+	return slug
+}
+
+// canonicalSlug reads the entry slug from the page's canonical link
+func canonicalSlug(doc *goquery.Document) string {
+	href, exists := doc.Find("link[rel='canonical']").Attr("href")
+	if !exists || !strings.Contains(href, "/rechtschreibung/") {
+		return ""
+	}
+	return entrySlug(href)
+}
+
+// fetchWordDoc fetches the HTML document for a word and returns it with the page URL
+func (s *DudenScraper) fetchWordDoc(word string) (*goquery.Document, string, error) {
 	encodedWord := url.QueryEscape(word)
 	wordURL := fmt.Sprintf("%s/rechtschreibung/%s", s.baseURL, encodedWord)
 
@@ -177,7 +371,7 @@ func (s *DudenScraper) fetchWordDoc(word string) (*goquery.Document, error) {
 		// Check if it's an error page
 		title := doc.Find("title").Text()
 		if !strings.Contains(title, "Fehlermeldung") {
-			return doc, nil
+			return doc, wordURL, nil
 		}
 	}
 
@@ -185,31 +379,54 @@ func (s *DudenScraper) fetchWordDoc(word string) (*goquery.Document, error) {
 	fmt.Printf("Word '%s' not found. Searching for alternatives...\n", word)
 	suggestions, err := s.GetSuggestions(word)
 	if err != nil || len(suggestions) == 0 {
-		return nil, fmt.Errorf("no alternatives found for '%s'", word)
+		return nil, "", fmt.Errorf("no alternatives found for '%s'", word)
 	}
 
 	// Try each suggestion
 	for _, suggestion := range suggestions {
 		fmt.Printf("Trying alternative: %s (%s)\n", suggestion.Text, suggestion.Link)
 
-		// Add a small delay to avoid rate limiting
-		time.Sleep(time.Duration(rand.Intn(2000)+1000) * time.Millisecond)
-
 		doc, err := s.makeRequest(suggestion.Link)
 		if err == nil {
-			return doc, nil
+			return doc, suggestion.Link, nil
 		}
 	}
 
-	return nil, errors.New("failed to find any valid alternatives")
+	return nil, "", errors.New("failed to find any valid alternatives")
 }
 
-// makeRequest makes an HTTP request and returns a goquery document
+// makeRequest makes a rate-limited HTTP request and returns a goquery document.
+// Network errors, 429 and 5xx responses are retried with exponential backoff.
 func (s *DudenScraper) makeRequest(url string) (*goquery.Document, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < s.retries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1000*(1<<(attempt-1))+rand.Intn(500)) * time.Millisecond
+			time.Sleep(backoff)
+		}
+
+		doc, retry, err := s.doRequest(url)
+		if err == nil {
+			return doc, nil
+		}
+		if !retry {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// doRequest performs a single request and reports whether a failure is worth retrying
+func (s *DudenScraper) doRequest(url string) (*goquery.Document, bool, error) {
+	s.limiter.Wait()
+
 	// Create a new request
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// Add headers
@@ -220,23 +437,26 @@ func (s *DudenScraper) makeRequest(url string) (*goquery.Document, error) {
 	// Make the request
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, true, err
 	}
 	defer resp.Body.Close()
 
 	// Check status code
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.New("page not found")
+		return nil, false, errors.New("page not found")
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return nil, true, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	// Parse the HTML document
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	return doc, nil
+	return doc, false, nil
 }
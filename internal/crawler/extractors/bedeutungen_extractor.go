@@ -2,10 +2,16 @@
 package extractors
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/PuerkitoBio/goquery"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 )
 
+// meaningItemSelector matches both top-level and nested meaning list items
+const meaningItemSelector = ".enumeration__item, .enumeration__sub-item"
+
 // BedeutungenExtractor extracts meanings and examples
 type BedeutungenExtractor struct {
 	*BaseExtractor
@@ -23,111 +29,129 @@ func (e *BedeutungenExtractor) Extract() interface{} {
 	return e.extractMeanings()
 }
 
-// extractMeanings extracts multiple meanings, grammar, examples, and images
+// extractMeanings builds the meaning tree from the numbered "Bedeutungen" list.
+// Pages with a single meaning use a "Bedeutung" section without numbering instead.
 func (e *BedeutungenExtractor) extractMeanings() []models.Meaning {
 	var meanings []models.Meaning
 
-	e.Doc.Find("#bedeutungen .enumeration__item").Each(func(i int, s *goquery.Selection) {
-		// Extract parent meaning
-		parentMeaning := e.extractSectionData(s)
-
-		// Extract sub-meanings
-		s.Find(".enumeration__sub-item").Each(func(j int, subSec *goquery.Selection) {
-			subMeaning := e.extractSectionData(subSec)
-
-			// If sub-meaning has the same text as parent, merge them
-			if subMeaning.Text == parentMeaning.Text {
-				e.mergeData(&parentMeaning, subMeaning)
-			} else {
-				parentMeaning.SubMeanings = append(parentMeaning.SubMeanings, subMeaning)
-			}
-		})
-
-		meanings = append(meanings, parentMeaning)
+	e.Doc.Find("#bedeutungen " + meaningItemSelector).Each(func(i int, s *goquery.Selection) {
+		// Only start from items that are not nested inside another meaning
+		if s.Parent().Closest(meaningItemSelector).Length() > 0 {
+			return
+		}
+		meanings = append(meanings, e.extractMeaningTree(s, fmt.Sprintf("%d", len(meanings)+1), 0))
 	})
 
-	// If no meanings found, add a default one
 	if len(meanings) == 0 {
-		meanings = append(meanings, models.Meaning{
-			Text:         "n/a",
-			Grammar:      "n/a",
-			Examples:     []string{},
-			Idioms:       []string{},
-			Image:        "n/a",
-			ImageCaption: "n/a",
-			SubMeanings:  []models.Meaning{},
-			TupleInfo:    map[string]string{},
+		e.Doc.Find("#bedeutung").First().Each(func(i int, s *goquery.Selection) {
+			meaning := e.extractSectionData(s, s.Find("p").First().Text())
+			if meaning.Text != "" || len(meaning.Examples) > 0 {
+				meanings = append(meanings, meaning)
+			}
 		})
 	}
 
 	return meanings
 }
 
-// extractSectionData extracts data from a single meaning or sub-meaning section
-func (e *BedeutungenExtractor) extractSectionData(section *goquery.Selection) models.Meaning {
-	meaningText := section.Find(".enumeration__text").Text()
-	meaningText = e.CleanText(meaningText)
+// extractMeaningTree extracts a meaning item and, recursively, all of its sub-items.
+// fallbackNumber is used when the item carries no "Bedeutung-…" id.
+func (e *BedeutungenExtractor) extractMeaningTree(item *goquery.Selection, fallbackNumber string, depth int) models.Meaning {
+	text := ""
+	e.ownFind(item, ".enumeration__text").First().Each(func(i int, s *goquery.Selection) {
+		text = s.Text()
+	})
+
+	meaning := e.extractSectionData(item, text)
+	meaning.Number = e.meaningNumber(item, fallbackNumber)
+
+	// Direct children are the meaning items whose closest enclosing item is this one
+	item.Find(meaningItemSelector).Each(func(i int, sub *goquery.Selection) {
+		if !sub.Parent().Closest(meaningItemSelector).IsSelection(item) {
+			return
+		}
+		subNumber := models.SubMeaningNumber(meaning.Number, len(meaning.SubMeanings), depth+1)
+		meaning.SubMeanings = append(meaning.SubMeanings, e.extractMeaningTree(sub, subNumber, depth+1))
+	})
+
+	return meaning
+}
+
+// meaningNumber returns Duden's own numbering ("1", "1a", "2b") from the item id
+func (e *BedeutungenExtractor) meaningNumber(item *goquery.Selection, fallback string) string {
+	if id, exists := item.Attr("id"); exists {
+		if number := strings.TrimPrefix(id, "Bedeutung-"); number != id && number != "" {
+			return number
+		}
+	}
+	return fallback
+}
+
+// ownFind finds elements below item that do not belong to a nested meaning item
+func (e *BedeutungenExtractor) ownFind(item *goquery.Selection, selector string) *goquery.Selection {
+	return item.Find(selector).FilterFunction(func(i int, s *goquery.Selection) bool {
+		owner := s.Closest(meaningItemSelector)
+		return owner.Length() == 0 || owner.IsSelection(item)
+	})
+}
 
+// extractSectionData extracts the data owned directly by a single meaning section
+func (e *BedeutungenExtractor) extractSectionData(section *goquery.Selection, text string) models.Meaning {
 	// Extract examples and idioms
 	exampleData := e.extractExamples(section)
 
 	// Extract image and caption
 	var imageURL, imageCaption string
-	section.Find(".depiction a").Each(func(i int, s *goquery.Selection) {
+	e.ownFind(section, ".depiction a").Each(func(i int, s *goquery.Selection) {
 		if href, exists := s.Attr("href"); exists {
 			imageURL = href
 		}
 	})
 
-	section.Find(".depiction__caption").Each(func(i int, s *goquery.Selection) {
+	e.ownFind(section, ".depiction__caption").Each(func(i int, s *goquery.Selection) {
 		imageCaption = e.CleanText(s.Text())
 	})
 
 	// Extract tuple info
 	tupleInfo := e.parseTupleInfo(section)
-	grammar, exists := tupleInfo["Grammatik"]
-	if exists {
-		delete(tupleInfo, "Grammatik")
+	grammar := tupleInfo["Grammatik"]
+	delete(tupleInfo, "Grammatik")
+
+	// Labels come from the "Gebrauch" tuple and from a leading annotation in the text
+	text, labels := parseLabels(e.CleanText(text))
+	if gebrauch, exists := tupleInfo["Gebrauch"]; exists {
+		if found, ok := classifyLabels(strings.Split(gebrauch, ",")); ok {
+			labels = mergeLabels(labels, found)
+			delete(tupleInfo, "Gebrauch")
+		}
 	}
 
-	if imageURL == "" {
-		imageURL = "n/a"
-	}
-	if imageCaption == "" {
-		imageCaption = "n/a"
-	}
-	if grammar == "" {
-		grammar = "n/a"
+	if len(tupleInfo) == 0 {
+		tupleInfo = nil
 	}
 
 	return models.Meaning{
-		Text:         meaningText,
+		Text:         text,
 		Grammar:      grammar,
-		Examples:     exampleData["examples"],
-		Idioms:       exampleData["idioms"],
+		Examples:     e.parseExamples(exampleData["examples"]),
+		Idioms:       e.parseIdioms(exampleData["idioms"]),
 		Image:        imageURL,
 		ImageCaption: imageCaption,
-		SubMeanings:  []models.Meaning{},
 		TupleInfo:    tupleInfo,
+		Labels:       labels,
 	}
 }
 
-// parseTupleInfo parses all <dl class='tuple'> blocks in the current container
+// parseTupleInfo parses the <dl class='tuple'> blocks owned by the current container
 func (e *BedeutungenExtractor) parseTupleInfo(container *goquery.Selection) map[string]string {
 	result := make(map[string]string)
 
-	container.Find("dl.tuple").Each(func(i int, s *goquery.Selection) {
+	e.ownFind(container, "dl.tuple").Each(func(i int, s *goquery.Selection) {
 		s.Find("dt.tuple__key").Each(func(j int, dt *goquery.Selection) {
 			dtText := e.CleanText(dt.Text())
 
-			// Find the corresponding dd
-			ddText := ""
-			dt.NextAll().Each(func(k int, dd *goquery.Selection) {
-				if dd.Is("dd.tuple__val") {
-					ddText = e.CleanText(dd.Text())
-					return
-				}
-			})
+			// The value is the first dd following the key
+			ddText := e.CleanText(dt.NextAllFiltered("dd.tuple__val").First().Text())
 
 			if dtText != "" && ddText != "" {
 				result[dtText] = ddText
@@ -138,85 +162,40 @@ func (e *BedeutungenExtractor) parseTupleInfo(container *goquery.Selection) map[
 	return result
 }
 
-// extractExamples extracts both general examples and idioms
+// extractExamples extracts both general examples and idioms owned by the section
 func (e *BedeutungenExtractor) extractExamples(section *goquery.Selection) map[string][]string {
-	result := map[string][]string{
-		"examples": {},
-		"idioms":   {},
-	}
+	result := map[string][]string{}
 
-	section.Find("dl.note").Each(func(i int, s *goquery.Selection) {
-		title := s.Find("dt.note__title").Text()
-
-		if title != "" && e.CleanText(title) == "Wendungen, Redensarten, Sprichwörter" {
-			// Extract idioms
-			s.Find("li").Each(func(j int, li *goquery.Selection) {
-				idiom := e.CleanText(li.Text())
-				if idiom != "" {
-					result["idioms"] = append(result["idioms"], idiom)
-				}
-			})
-		} else {
-			// Extract general examples
-			s.Find("li").Each(func(j int, li *goquery.Selection) {
-				example := e.CleanText(li.Text())
-				if example != "" {
-					result["examples"] = append(result["examples"], example)
-				}
-			})
+	e.ownFind(section, "dl.note").Each(func(i int, s *goquery.Selection) {
+		key := "examples"
+		if e.CleanText(s.Find("dt.note__title").Text()) == "Wendungen, Redensarten, Sprichwörter" {
+			key = "idioms"
 		}
+
+		s.Find("li").Each(func(j int, li *goquery.Selection) {
+			if text := e.CleanText(li.Text()); text != "" {
+				result[key] = append(result[key], text)
+			}
+		})
 	})
 
 	return result
 }
 
-// mergeData merges data from sub-meaning into the parent meaning
-func (e *BedeutungenExtractor) mergeData(parent *models.Meaning, sub models.Meaning) {
-	// Merge examples
-	for _, example := range sub.Examples {
-		// Check if example already exists
-		exists := false
-		for _, parentExample := range parent.Examples {
-			if parentExample == example {
-				exists = true
-				break
-			}
-		}
-		if !exists {
-			parent.Examples = append(parent.Examples, example)
-		}
-	}
-
-	// Merge idioms
-	for _, idiom := range sub.Idioms {
-		// Check if idiom already exists
-		exists := false
-		for _, parentIdiom := range parent.Idioms {
-			if parentIdiom == idiom {
-				exists = true
-				break
-			}
-		}
-		if !exists {
-			parent.Idioms = append(parent.Idioms, idiom)
-		}
-	}
-
-	// If parent's grammar is n/a but sub has grammar, update parent's
-	if (parent.Grammar == "" || parent.Grammar == "n/a") && sub.Grammar != "n/a" {
-		parent.Grammar = sub.Grammar
-	}
-
-	// If parent image is n/a but sub has an image
-	if (parent.Image == "" || parent.Image == "n/a") && sub.Image != "n/a" {
-		parent.Image = sub.Image
-		parent.ImageCaption = sub.ImageCaption
+// parseExamples turns raw example strings into examples with glosses and labels separated
+func (e *BedeutungenExtractor) parseExamples(raw []string) []models.Example {
+	var examples []models.Example
+	for _, text := range raw {
+		examples = append(examples, parseExample(text))
 	}
+	return examples
+}
 
-	// Merge tuple_info
-	for k, v := range sub.TupleInfo {
-		if _, exists := parent.TupleInfo[k]; !exists || parent.TupleInfo[k] == "" {
-			parent.TupleInfo[k] = v
-		}
+// parseIdioms turns raw idiom strings into idioms with their explanations separated
+func (e *BedeutungenExtractor) parseIdioms(raw []string) []models.Idiom {
+	var idioms []models.Idiom
+	for _, text := range raw {
+		idioms = append(idioms, parseIdiom(text))
 	}
+	return idioms
 }
@@ -0,0 +1,83 @@
+// internal/crawler/extractors/collocations_extractor.go
+package extractors
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// collocationWeight matches the weight modifier of a word cloud item, e.g. "tag-cloud__item--4"
+var collocationWeight = regexp.MustCompile(`--(\d+)\b`)
+
+// CollocationsExtractor extracts the typical word combinations ("Typische Verbindungen")
+type CollocationsExtractor struct {
+	*BaseExtractor
+}
+
+// NewCollocationsExtractor creates a new CollocationsExtractor
+func NewCollocationsExtractor(doc *goquery.Document) Extractor {
+	return &CollocationsExtractor{
+		BaseExtractor: NewBaseExtractor(doc),
+	}
+}
+
+// Extract extracts the collocations. Duden renders them as a word cloud whose
+// items carry a weight; strengths are normalized so the heaviest item is 1.
+func (e *CollocationsExtractor) Extract() interface{} {
+	var collocations []models.Collocation
+	var weights []float64
+	maxWeight := 0.0
+
+	e.Doc.Find("#kookkurrenzen li, #typische-verbindungen li").Each(func(i int, s *goquery.Selection) {
+		text := e.CleanText(s.Text())
+		if text == "" {
+			return
+		}
+
+		link, _ := s.Find("a").Attr("href")
+		weight := e.weight(s)
+		if weight > maxWeight {
+			maxWeight = weight
+		}
+
+		collocations = append(collocations, models.Collocation{Text: text, Link: link})
+		weights = append(weights, weight)
+	})
+
+	for i := range collocations {
+		if maxWeight > 0 {
+			collocations[i].Strength = weights[i] / maxWeight
+		} else {
+			// Without weights all combinations count as equally strong
+			collocations[i].Strength = 1
+		}
+	}
+
+	return collocations
+}
+
+// weight reads the weight of a word cloud item from its data attribute or class
+func (e *CollocationsExtractor) weight(s *goquery.Selection) float64 {
+	items := s.Find("a").AddSelection(s)
+
+	weight := 0.0
+	items.Each(func(i int, item *goquery.Selection) {
+		if value, exists := item.Attr("data-weight"); exists {
+			if w, err := strconv.ParseFloat(value, 64); err == nil && w > weight {
+				weight = w
+			}
+		}
+		if class, exists := item.Attr("class"); exists {
+			if match := collocationWeight.FindStringSubmatch(class); match != nil {
+				if w, err := strconv.ParseFloat(match[1], 64); err == nil && w > weight {
+					weight = w
+				}
+			}
+		}
+	})
+
+	return weight
+}
@@ -8,9 +8,16 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// DocumentFetcher fetches additional pages for extractors that need more than
+// the entry page, such as the dedicated synonyms page
+type DocumentFetcher interface {
+	FetchDocument(link string) (*goquery.Document, error)
+}
+
 // ExtractorFactory creates and manages extractors
 type ExtractorFactory struct {
 	extractors map[string]func(*goquery.Document) Extractor
+	fetcher    DocumentFetcher
 }
 
 // NewExtractorFactory creates a new ExtractorFactory
@@ -49,7 +56,7 @@ func (f *ExtractorFactory) registerExtractors() {
 
 	// Register synonyms extractor
 	f.extractors["synonyme"] = func(doc *goquery.Document) Extractor {
-		return NewSynonymeExtractor(doc)
+		return NewSynonymeExtractor(doc, f.fetcher)
 	}
 
 	// Register origin extractor
@@ -57,12 +64,38 @@ func (f *ExtractorFactory) registerExtractors() {
 		return NewHerkunftExtractor(doc)
 	}
 
+	// Register antonyms extractor
+	f.extractors["gegenwort"] = func(doc *goquery.Document) Extractor {
+		return NewAntonymExtractor(doc)
+	}
+
+	// Register typical collocations extractor
+	f.extractors["typische_verbindungen"] = func(doc *goquery.Document) Extractor {
+		return NewCollocationsExtractor(doc)
+	}
+
+	// Register compounds extractor
+	f.extractors["zusammensetzungen"] = func(doc *goquery.Document) Extractor {
+		return NewCompoundExtractor(doc)
+	}
+
+	// Register word family extractor
+	f.extractors["wortfamilie"] = func(doc *goquery.Document) Extractor {
+		return NewWordFamilyExtractor(doc)
+	}
+
 	// Register fun facts extractor
 	f.extractors["wussten_sie_schon"] = func(doc *goquery.Document) Extractor {
 		return NewWusstenSieSchonExtractor(doc)
 	}
 }
 
+// SetFetcher sets the fetcher used by extractors that load additional pages.
+// Without a fetcher those extractors only use the entry page.
+func (f *ExtractorFactory) SetFetcher(fetcher DocumentFetcher) {
+	f.fetcher = fetcher
+}
+
 // CreateExtractor creates an extractor for the given section
 func (f *ExtractorFactory) CreateExtractor(section string, doc *goquery.Document) (Extractor, error) {
 	constructor, exists := f.extractors[section]
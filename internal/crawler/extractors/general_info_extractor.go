@@ -45,7 +45,7 @@ func (e *GeneralInfoExtractor) extractArticle() string {
 func (e *GeneralInfoExtractor) extractWordType() []string {
 	wordTypeText := e.ExtractText(".tuple__key:-soup-contains('Wortart') + .tuple__val", "")
 	if wordTypeText == "" {
-		return []string{"unknown"}
+		return nil
 	}
 
 	// Split by comma and trim spaces
@@ -75,8 +75,6 @@ func (e *GeneralInfoExtractor) extractFrequency() string {
 			frequency = "low"
 		case 1:
 			frequency = "very_low"
-		default:
-			frequency = "unknown"
 		}
 	})
 
@@ -93,7 +91,7 @@ func (e *GeneralInfoExtractor) extractPronunciation() []models.Pronunciation {
 		phonetic = e.CleanText(phonetic)
 
 		// Extract audio link
-		audioLink := "no_audio_available"
+		var audioLink string
 		s.Find("a.pronunciation-guide__sound[data-duden-ref-type='audio']").Each(func(i int, a *goquery.Selection) {
 			if href, exists := a.Attr("href"); exists {
 				audioLink = href
@@ -127,14 +125,5 @@ func (e *GeneralInfoExtractor) extractPronunciation() []models.Pronunciation {
 		})
 	})
 
-	// If no pronunciations found, add a default one
-	if len(pronunciations) == 0 {
-		pronunciations = append(pronunciations, models.Pronunciation{
-			Word:     "n/a",
-			Phonetic: "n/a",
-			Audio:    "no_audio_available",
-		})
-	}
-
 	return pronunciations
 }
@@ -0,0 +1,150 @@
+// internal/crawler/extractors/glosses.go
+package extractors
+
+import (
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// parenthetical is the byte range of a top-level "( … )" group, including the parentheses
+type parenthetical struct {
+	start int
+	end   int
+}
+
+// findParentheticals returns all top-level, balanced parenthesized groups in text
+func findParentheticals(text string) []parenthetical {
+	var groups []parenthetical
+	depth, start := 0, 0
+
+	for i, r := range text {
+		switch r {
+		case '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ')':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				groups = append(groups, parenthetical{start: start, end: i + 1})
+			}
+		}
+	}
+
+	return groups
+}
+
+// splitAnnotation separates labels from an explanation inside a parenthesis.
+// Duden writes "umgangssprachlich: sehr billig" or "umgangssprachlich; von zu Hause".
+// A parenthesis that only contains labels returns an empty explanation.
+func splitAnnotation(inner string) (models.Labels, string) {
+	inner = strings.TrimSpace(inner)
+
+	if labels, ok := classifyLabels(strings.Split(inner, ",")); ok && !labels.IsEmpty() {
+		return labels, ""
+	}
+
+	if idx := strings.IndexAny(inner, ":;"); idx > 0 {
+		if labels, ok := classifyLabels(strings.Split(inner[:idx], ",")); ok && !labels.IsEmpty() {
+			return labels, strings.TrimSpace(inner[idx+1:])
+		}
+	}
+
+	return models.Labels{}, inner
+}
+
+// optionalWords are the words Duden writes in optional parts of an example,
+// e.g. "(sich) etwas vornehmen" or "jemanden (mit etwas) überraschen"
+var optionalWords = map[string]bool{
+	"sich": true, "etwas": true, "jemand": true, "jemandem": true, "jemanden": true, "jemandes": true,
+	"einer": true, "Sache": true, "ein": true, "eine": true, "einen": true, "einem": true, "eines": true,
+	"an": true, "auf": true, "aus": true, "bei": true, "durch": true, "für": true, "gegen": true, "in": true,
+	"mit": true, "nach": true, "über": true, "um": true, "unter": true, "von": true, "vor": true, "zu": true,
+}
+
+// isOptionalPart reports whether a parenthesis inside an example is an optional
+// part of it rather than an explanation. Explanations contain several words that
+// are not just placeholders like "etwas" or "jemandem"; single words are optional.
+func isOptionalPart(inner string) bool {
+	words := strings.Fields(inner)
+	if len(words) <= 1 {
+		return true
+	}
+	for _, word := range words {
+		if !optionalWords[word] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseExample splits a raw example such as "er ist nicht zu Hause (nicht in seiner
+// Wohnung)" into its text, the inline gloss and its labels. A parenthesis is a gloss
+// when it starts with labels, closes the example or explains it in several words;
+// optional parts like "jemanden (mit etwas) überraschen" stay in the text.
+func parseExample(raw string) models.Example {
+	text, labels := parseLabels(raw)
+
+	var glosses []string
+	var sb strings.Builder
+	last := 0
+	for _, group := range findParentheticals(text) {
+		inner := text[group.start+1 : group.end-1]
+		found, gloss := splitAnnotation(inner)
+		closing := strings.TrimSpace(text[group.end:]) == ""
+		if found.IsEmpty() && !closing && isOptionalPart(inner) {
+			continue
+		}
+		labels = mergeLabels(labels, found)
+		if gloss != "" {
+			glosses = append(glosses, gloss)
+		}
+		sb.WriteString(text[last:group.start])
+		last = group.end
+	}
+	sb.WriteString(text[last:])
+
+	return models.Example{
+		Text:   normalizeSpacing(sb.String()),
+		Gloss:  strings.Join(glosses, "; "),
+		Labels: labels,
+	}
+}
+
+// parseIdiom splits a raw idiom such as "in den sauren Apfel beißen (etwas Unangenehmes
+// notgedrungen tun)" into the idiom and its explanation. The explanation is the
+// parenthesis that closes the entry and may start with labels ("umgangssprachlich: …").
+func parseIdiom(raw string) models.Idiom {
+	text, labels := parseLabels(raw)
+
+	groups := findParentheticals(text)
+	if len(groups) == 0 {
+		return models.Idiom{Text: text, Labels: labels}
+	}
+
+	group := groups[len(groups)-1]
+	if strings.TrimSpace(text[group.end:]) != "" {
+		return models.Idiom{Text: text, Labels: labels}
+	}
+
+	found, explanation := splitAnnotation(text[group.start+1 : group.end-1])
+	return models.Idiom{
+		Text:        normalizeSpacing(text[:group.start]),
+		Explanation: explanation,
+		Labels:      mergeLabels(labels, found),
+	}
+}
+
+// normalizeSpacing collapses whitespace left behind after removing parentheses
+func normalizeSpacing(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, punct := range []string{",", ";", ":", ".", "!", "?"} {
+		text = strings.ReplaceAll(text, " "+punct, punct)
+	}
+	return text
+}
@@ -0,0 +1,68 @@
+package extractors
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// dudenMeaning wraps example list items in the markup of a Duden meaning section
+func dudenMeaning(examples ...string) string {
+	var items strings.Builder
+	for _, example := range examples {
+		items.WriteString(`<li>` + example + `</li>`)
+	}
+	return `<div class="division" id="bedeutungen"><ol class="enumeration">` +
+		`<li class="enumeration__item" id="Bedeutung-1"><div class="enumeration__text">Gebäude, das Menschen zum Wohnen dient</div>` +
+		`<dl class="note"><dt class="note__title">Beispiele</dt><dd><ul class="note__list">` + items.String() + `</ul></dd></dl>` +
+		`</li></ol></div>`
+}
+
+func TestExampleParentheticals(t *testing.T) {
+	tests := []struct {
+		html string
+		want models.Example
+	}{
+		{
+			html: `(sich) etwas vornehmen`,
+			want: models.Example{Text: "(sich) etwas vornehmen"},
+		},
+		{
+			html: `jemanden (mit etwas) überraschen`,
+			want: models.Example{Text: "jemanden (mit etwas) überraschen"},
+		},
+		{
+			html: `Haus an Haus (nebeneinander) wohnen`,
+			want: models.Example{Text: "Haus an Haus (nebeneinander) wohnen"},
+		},
+		{
+			html: `das ganze Haus (alle Hausbewohner) war auf den Beinen`,
+			want: models.Example{Text: "das ganze Haus war auf den Beinen", Gloss: "alle Hausbewohner"},
+		},
+		{
+			html: `außer Haus sein (nicht zu Hause)`,
+			want: models.Example{Text: "außer Haus sein", Gloss: "nicht zu Hause"},
+		},
+		{
+			html: `ein Haus (umgangssprachlich: eine Bude) mieten`,
+			want: models.Example{Text: "ein Haus mieten", Gloss: "eine Bude", Labels: models.Labels{Register: []string{"umgangssprachlich"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(dudenMeaning(tt.html)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		meanings := NewBedeutungenExtractor(doc).Extract().([]models.Meaning)
+		if len(meanings) != 1 || len(meanings[0].Examples) != 1 {
+			t.Fatalf("%s: got meanings %+v", tt.html, meanings)
+		}
+		if got := meanings[0].Examples[0]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.html, got, tt.want)
+		}
+	}
+}
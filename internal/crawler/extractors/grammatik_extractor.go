@@ -2,11 +2,24 @@
 package extractors
 
 import (
+	"regexp"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// formDeterminers are articles and pronouns that precede a form in declension
+// and conjugation tables ("des Hauses", "du gehst")
+var formDeterminers = map[string]bool{
+	"der": true, "die": true, "das": true, "des": true, "dem": true, "den": true,
+	"ein": true, "eine": true, "eines": true, "einem": true, "einen": true, "einer": true,
+	"ich": true, "du": true, "er": true, "sie": true, "es": true, "wir": true, "ihr": true,
+	"man": true, "zu": true, "am": true,
+}
+
+// optionalLetters matches optional letters in forms such as "Haus[e]s"
+var optionalLetters = regexp.MustCompile(`\[([^\]]*)\]`)
+
 // GrammatikExtractor extracts grammatical information
 type GrammatikExtractor struct {
 	*BaseExtractor
@@ -24,7 +37,68 @@ func (e *GrammatikExtractor) Extract() interface{} {
 	return map[string]interface{}{
 		"paragraphs": e.extractParagraphs(),
 		"links":      e.extractLinks(),
+		"forms":      e.extractForms(),
+	}
+}
+
+// extractForms collects the inflected forms from the declension or conjugation
+// tables and from the "Genitiv: des Hauses, Plural: die Häuser" summary
+func (e *GrammatikExtractor) extractForms() []string {
+	var forms []string
+	seen := make(map[string]bool)
+
+	add := func(raw string) {
+		for _, form := range expandForms(e.CleanText(raw)) {
+			if !seen[form] {
+				seen[form] = true
+				forms = append(forms, form)
+			}
+		}
 	}
+
+	e.Doc.Find("#grammatik table td").Each(func(i int, s *goquery.Selection) {
+		add(s.Text())
+	})
+
+	for _, detail := range e.extractParagraphs()["details"].([]map[string]string) {
+		if detail["type"] != "base_form" {
+			add(detail["value"])
+		}
+	}
+
+	return forms
+}
+
+// expandForms turns a table cell such as "des Haus[e]s, Hauses" or "du gehst"
+// into the bare word forms it contains
+func expandForms(cell string) []string {
+	var forms []string
+
+	for _, variant := range strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == '/' }) {
+		var words []string
+		for _, word := range strings.Fields(variant) {
+			word = strings.Trim(word, "()–-.;:")
+			if word == "" || formDeterminers[strings.ToLower(word)] {
+				continue
+			}
+			words = append(words, word)
+		}
+		// Separable verbs list the particle last ("ich komme an"); keep the verb
+		if len(words) == 0 {
+			continue
+		}
+		form := words[0]
+
+		if optionalLetters.MatchString(form) {
+			forms = append(forms,
+				optionalLetters.ReplaceAllString(form, "$1"),
+				optionalLetters.ReplaceAllString(form, ""))
+			continue
+		}
+		forms = append(forms, form)
+	}
+
+	return forms
 }
 
 // extractLinks extracts all links from the 'Grammatik' section
@@ -34,8 +108,8 @@ func (e *GrammatikExtractor) extractLinks() []map[string]string {
 	e.Doc.Find("#grammatik a.more__link").Each(func(i int, s *goquery.Selection) {
 		text := e.CleanText(s.Text())
 		link, exists := s.Attr("href")
-		if !exists {
-			link = "N/A"
+		if !exists || text == "" {
+			return
 		}
 
 		linksData = append(linksData, map[string]string{
@@ -44,13 +118,6 @@ func (e *GrammatikExtractor) extractLinks() []map[string]string {
 		})
 	})
 
-	if len(linksData) == 0 {
-		linksData = append(linksData, map[string]string{
-			"text": "No links available",
-			"link": "N/A",
-		})
-	}
-
 	return linksData
 }
 
@@ -111,10 +178,6 @@ func (e *GrammatikExtractor) extractParagraphs() map[string]interface{} {
 		}
 	})
 
-	if text == "" {
-		text = "No data"
-	}
-
 	return map[string]interface{}{
 		"text":    text,
 		"details": details,
@@ -8,7 +8,27 @@ import (
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 )
 
-// HerkunftExtractor extracts word origins
+// languageBases are the language adjectives Duden uses in etymologies
+var languageBases = map[string]bool{
+	"arabisch": true, "dänisch": true, "deutsch": true, "englisch": true,
+	"finnisch": true, "französisch": true, "fränkisch": true, "friesisch": true,
+	"germanisch": true, "gotisch": true, "griechisch": true, "hebräisch": true,
+	"indisch": true, "isländisch": true, "italienisch": true, "japanisch": true,
+	"jiddisch": true, "katalanisch": true, "keltisch": true, "chinesisch": true,
+	"lateinisch": true, "nordisch": true, "niederländisch": true, "norwegisch": true,
+	"persisch": true, "polnisch": true, "portugiesisch": true, "provenzalisch": true,
+	"romanisch": true, "rumänisch": true, "russisch": true, "sächsisch": true,
+	"schwedisch": true, "slawisch": true, "spanisch": true, "tschechisch": true,
+	"türkisch": true, "ungarisch": true,
+}
+
+// languagePrefixes qualify a language adjective, e.g. "althochdeutsch" or "spätlateinisch"
+var languagePrefixes = []string{
+	"alt", "mittel", "neu", "spät", "früh", "vulgär", "kirchen",
+	"nieder", "hoch", "ober", "west", "ost", "nord", "süd", "gemein", "ur", "indo",
+}
+
+// HerkunftExtractor extracts the etymology of a word
 type HerkunftExtractor struct {
 	*BaseExtractor
 }
@@ -20,54 +40,142 @@ func NewHerkunftExtractor(doc *goquery.Document) Extractor {
 	}
 }
 
-// Extract extracts word origins
+// etymologyToken is a piece of the Herkunft paragraph: a plain word, a linked
+// entry or an emphasized word form
+type etymologyToken struct {
+	text   string
+	link   string
+	isForm bool
+}
+
+// Extract extracts the etymology as readable text plus its language-tagged source words
 func (e *HerkunftExtractor) Extract() interface{} {
-	var origins []models.Origin
+	var paragraphs []string
+	var tokens []etymologyToken
 
-	// Select the paragraph inside the Herkunft section
 	e.Doc.Find("#herkunft p").Each(func(i int, s *goquery.Selection) {
-		// Process each element in the paragraph
-		s.Contents().Each(func(j int, content *goquery.Selection) {
-			if content.Is("a") {
-				// If it's a link
-				text := e.CleanText(content.Text())
-				href, _ := content.Attr("href")
-
-				if text != "" {
-					origins = append(origins, models.Origin{
-						Word: text,
-						Link: href,
-					})
-				}
-			} else {
-				// If it's plain text
-				text := content.Text()
-				if text == "" {
-					return
-				}
+		if text := e.CleanText(s.Text()); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+		tokens = append(tokens, e.tokenize(s)...)
+	})
+
+	if len(paragraphs) == 0 {
+		return (*models.Etymology)(nil)
+	}
+
+	return &models.Etymology{
+		Text:    strings.Join(strings.Fields(strings.Join(paragraphs, " ")), " "),
+		Sources: e.extractSources(tokens),
+	}
+}
+
+// EtymologyFromOrigin converts the origin stored by older versions, the words
+// of the Herkunft paragraph one by one with the links among them, into an
+// etymology with language-tagged sources
+func EtymologyFromOrigin(origin []models.EtymologySource) *models.Etymology {
+	if len(origin) == 0 {
+		return nil
+	}
+
+	words := make([]string, 0, len(origin))
+	tokens := make([]etymologyToken, 0, len(origin))
+	for _, item := range origin {
+		words = append(words, item.Word)
+		tokens = append(tokens, etymologyToken{text: item.Word, link: item.Link, isForm: item.Link != ""})
+	}
+
+	return &models.Etymology{
+		Text:    strings.Join(strings.Fields(strings.Join(words, " ")), " "),
+		Sources: (&HerkunftExtractor{}).extractSources(tokens),
+	}
+}
+
+// tokenize splits a paragraph into plain words, links and emphasized forms
+func (e *HerkunftExtractor) tokenize(s *goquery.Selection) []etymologyToken {
+	var tokens []etymologyToken
 
-				// Split the text by spaces and add each word
-				words := strings.Fields(text)
-				for _, word := range words {
-					word = e.CleanText(word)
-					if word != "" {
-						origins = append(origins, models.Origin{
-							Word: word,
-							Link: "",
-						})
-					}
+	s.Contents().Each(func(i int, content *goquery.Selection) {
+		switch {
+		case content.Is("a"):
+			href, _ := content.Attr("href")
+			if text := e.CleanText(content.Text()); text != "" {
+				tokens = append(tokens, etymologyToken{text: text, link: href, isForm: true})
+			}
+		case content.Is("em, i"):
+			if text := e.CleanText(content.Text()); text != "" {
+				tokens = append(tokens, etymologyToken{text: text, isForm: true})
+			}
+		default:
+			for _, word := range strings.Fields(content.Text()) {
+				if word = e.CleanText(word); word != "" {
+					tokens = append(tokens, etymologyToken{text: word})
 				}
 			}
-		})
+		}
 	})
 
-	// If no origins found, add a default one
-	if len(origins) == 0 {
-		origins = append(origins, models.Origin{
-			Word: "No data available",
-			Link: "",
-		})
+	return tokens
+}
+
+// extractSources pairs source word forms with the language tags preceding them.
+// "mittelhochdeutsch, althochdeutsch hūs" yields hūs for both languages.
+func (e *HerkunftExtractor) extractSources(tokens []etymologyToken) []models.EtymologySource {
+	var sources []models.EtymologySource
+	var languages []string
+	continued := false // the previous language ended with a comma
+
+	for _, token := range tokens {
+		word := strings.TrimRight(token.text, ",;.:")
+
+		if !token.isForm && isLanguageTag(word) {
+			if !continued {
+				languages = nil
+			}
+			languages = append(languages, word)
+			continued = strings.HasSuffix(token.text, ",")
+			continue
+		}
+
+		// A form directly after a language tag is a source word, as are linked entries
+		if word != "" && (token.isForm || (len(languages) > 0 && !continued)) {
+			if len(languages) == 0 {
+				sources = append(sources, models.EtymologySource{Word: word, Link: token.link})
+			}
+			for _, language := range languages {
+				sources = append(sources, models.EtymologySource{
+					Word:     word,
+					Language: language,
+					Link:     token.link,
+				})
+			}
+		}
+
+		languages = nil
+		continued = false
 	}
 
-	return origins
+	return sources
+}
+
+// isLanguageTag reports whether a word is a (possibly qualified) language adjective
+func isLanguageTag(word string) bool {
+	word = strings.ToLower(word)
+	for {
+		if languageBases[word] {
+			return true
+		}
+
+		stripped := false
+		for _, prefix := range languagePrefixes {
+			if strings.HasPrefix(word, prefix) && len(word) > len(prefix) {
+				word = strings.TrimPrefix(word, prefix)
+				stripped = true
+				break
+			}
+		}
+		if !stripped {
+			return false
+		}
+	}
 }
@@ -0,0 +1,177 @@
+// internal/crawler/extractors/labels.go
+package extractors
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// figurativeMarkers are the prefixes Duden uses for transferred senses
+var figurativeMarkers = []string{
+	"〈in übertragener Bedeutung:〉",
+	"〈in übertragener Bedeutung〉",
+	"〈übertragen:〉",
+	"〈übertragen〉",
+}
+
+// registerLabels are Duden's style and register labels ("Gebrauch")
+var registerLabels = map[string]bool{
+	"abwertend":          true,
+	"bildungssprachlich": true,
+	"derb":               true,
+	"dichterisch":        true,
+	"emotional":          true,
+	"familiär":           true,
+	"gehoben":            true,
+	"Jargon":             true,
+	"ironisch":           true,
+	"landschaftlich":     true,
+	"Papierdeutsch":      true,
+	"regional":           true,
+	"salopp":             true,
+	"scherzhaft":         true,
+	"selten":             true,
+	"umgangssprachlich":  true,
+	"veraltend":          true,
+	"veraltet":           true,
+	"verhüllend":         true,
+	"vulgär":             true,
+	"nachdrücklich":      true,
+	"norddeutsch":        true,
+	"süddeutsch":         true,
+	"mitteldeutsch":      true,
+	"österreichisch":     true,
+	"schweizerisch":      true,
+	"historisch":         true,
+}
+
+// labelQualifiers modify a following label ("besonders umgangssprachlich") and are skipped
+var labelQualifiers = map[string]bool{
+	"auch":      true,
+	"besonders": true,
+	"meist":     true,
+	"oft":       true,
+	"und":       true,
+}
+
+// figurativeLabels mark a transferred sense when used as a register label
+var figurativeLabels = map[string]bool{
+	"übertragen": true,
+	"bildlich":   true,
+}
+
+// domainLabels are subject areas Duden marks without the usual "-sprache" suffix
+var domainLabels = map[string]bool{
+	"Biologie":           true,
+	"Botanik":            true,
+	"Chemie":             true,
+	"EDV":                true,
+	"Finanzwesen":        true,
+	"Geografie":          true,
+	"Informatik":         true,
+	"Kunst":              true,
+	"Mathematik":         true,
+	"Medizin":            true,
+	"Militär":            true,
+	"Musik":              true,
+	"Philosophie":        true,
+	"Physik":             true,
+	"Politik":            true,
+	"Psychologie":        true,
+	"Religion":           true,
+	"Sport":              true,
+	"Technik":            true,
+	"Wirtschaft":         true,
+	"Zoologie":           true,
+	"Sprachwissenschaft": true,
+	"Rechtswesen":        true,
+}
+
+// classifyLabels sorts raw label strings like "veraltet verhüllend" or
+// "Kaufmannssprache" into register, domain and figurative labels.
+// ok is false if any token is not a known label.
+func classifyLabels(raw []string) (labels models.Labels, ok bool) {
+	for _, label := range raw {
+		for _, token := range strings.Fields(label) {
+			switch {
+			case labelQualifiers[token]:
+				continue
+			case figurativeLabels[token]:
+				labels.Figurative = true
+			case registerLabels[token]:
+				labels.Register = append(labels.Register, token)
+			case isDomainLabel(token):
+				labels.Domain = append(labels.Domain, token)
+			default:
+				return labels, false
+			}
+		}
+	}
+	return labels, true
+}
+
+// isDomainLabel reports whether a token names a subject area
+func isDomainLabel(token string) bool {
+	if domainLabels[token] {
+		return true
+	}
+	first, _ := utf8.DecodeRuneInString(token)
+	return unicode.IsUpper(first) && strings.HasSuffix(token, "sprache")
+}
+
+// parseLabels strips leading label annotations from an example or meaning text.
+// It handles the figurative marker "〈in übertragener Bedeutung:〉" and a leading
+// parenthesis that contains only labels, e.g. "(veraltet verhüllend)".
+func parseLabels(text string) (string, models.Labels) {
+	var labels models.Labels
+	text = strings.TrimSpace(text)
+
+	for {
+		stripped := false
+
+		for _, marker := range figurativeMarkers {
+			if strings.HasPrefix(text, marker) {
+				labels.Figurative = true
+				text = strings.TrimSpace(strings.TrimPrefix(text, marker))
+				stripped = true
+			}
+		}
+
+		if strings.HasPrefix(text, "(") {
+			if end := strings.Index(text, ")"); end > 0 {
+				inner := strings.Split(text[1:end], ",")
+				if found, ok := classifyLabels(inner); ok && !found.IsEmpty() {
+					labels = mergeLabels(labels, found)
+					text = strings.TrimSpace(text[end+1:])
+					stripped = true
+				}
+			}
+		}
+
+		if !stripped {
+			return text, labels
+		}
+	}
+}
+
+// mergeLabels combines two label sets into new slices, so neither argument is modified
+func mergeLabels(a, b models.Labels) models.Labels {
+	return models.Labels{
+		Register:   concatLabels(a.Register, b.Register),
+		Domain:     concatLabels(a.Domain, b.Domain),
+		Figurative: a.Figurative || b.Figurative,
+	}
+}
+
+// concatLabels returns a fresh slice holding a followed by b, or nil if both are empty
+func concatLabels(a, b []string) []string {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	merged := make([]string, 0, len(a)+len(b))
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
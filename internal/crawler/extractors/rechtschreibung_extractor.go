@@ -29,7 +29,7 @@ func (e *RechtschreibungExtractor) extractSpelling() map[string]interface{} {
 	// Extract syllabic division (Worttrennung)
 	syllabicDivision := e.ExtractText(
 		"#rechtschreibung .tuple__key:contains('Worttrennung') + .tuple__val",
-		"N/A",
+		"",
 	)
 
 	// Extract general spelling examples from the first list
@@ -0,0 +1,96 @@
+// internal/crawler/extractors/related_words_extractor.go
+package extractors
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// RelatedWordsExtractor extracts linked words from a page section, such as
+// antonyms, compounds or the word family of an entry
+type RelatedWordsExtractor struct {
+	*BaseExtractor
+	sections  string   // sections listing the words
+	tupleKeys []string // tuple keys ("Gegenwort") whose values list the words
+}
+
+// NewAntonymExtractor creates an extractor for antonyms ("Gegenwort"), which Duden
+// lists in their own section or as a tuple next to a meaning
+func NewAntonymExtractor(doc *goquery.Document) Extractor {
+	return &RelatedWordsExtractor{
+		BaseExtractor: NewBaseExtractor(doc),
+		sections:      "#gegenwort, #gegenwoerter",
+		tupleKeys:     []string{"Gegenwort", "Gegenwörter"},
+	}
+}
+
+// NewCompoundExtractor creates an extractor for compounds ("Zusammensetzungen")
+func NewCompoundExtractor(doc *goquery.Document) Extractor {
+	return &RelatedWordsExtractor{
+		BaseExtractor: NewBaseExtractor(doc),
+		sections:      "#zusammensetzungen",
+	}
+}
+
+// NewWordFamilyExtractor creates an extractor for derived words ("Wortfamilie")
+func NewWordFamilyExtractor(doc *goquery.Document) Extractor {
+	return &RelatedWordsExtractor{
+		BaseExtractor: NewBaseExtractor(doc),
+		sections:      "#wortfamilie",
+	}
+}
+
+// Extract extracts the related words, without duplicates
+func (e *RelatedWordsExtractor) Extract() interface{} {
+	var words []models.RelatedWord
+	seen := make(map[string]bool)
+
+	add := func(s *goquery.Selection) {
+		e.extractWords(s, func(word models.RelatedWord) {
+			if !seen[word.Text] {
+				seen[word.Text] = true
+				words = append(words, word)
+			}
+		})
+	}
+
+	e.Doc.Find(e.sections).Each(func(i int, s *goquery.Selection) {
+		// Skip headings and "more" links of the section
+		s.Find("ul, ol, p, dd").Each(func(j int, list *goquery.Selection) {
+			add(list)
+		})
+	})
+
+	for _, key := range e.tupleKeys {
+		e.Doc.Find("dt.tuple__key").Each(func(i int, dt *goquery.Selection) {
+			if e.CleanText(dt.Text()) == key {
+				add(dt.NextAllFiltered("dd.tuple__val").First())
+			}
+		})
+	}
+
+	return words
+}
+
+// extractWords emits the linked words of an element, or its comma-separated
+// words if it has no links
+func (e *RelatedWordsExtractor) extractWords(s *goquery.Selection, emit func(models.RelatedWord)) {
+	links := s.Find("a").Not(".more__link")
+	if links.Length() > 0 {
+		links.Each(func(i int, a *goquery.Selection) {
+			if text := e.CleanText(a.Text()); text != "" {
+				href, _ := a.Attr("href")
+				emit(models.RelatedWord{Text: text, Link: href})
+			}
+		})
+		return
+	}
+
+	for _, part := range strings.Split(e.CleanText(s.Text()), ",") {
+		if text := strings.TrimSpace(part); text != "" {
+			emit(models.RelatedWord{Text: text})
+		}
+	}
+}
@@ -2,31 +2,49 @@
 package extractors
 
 import (
-	"net/http"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 )
 
-// SynonymeExtractor extracts synonyms
+// synonymGroupSelector matches one sense group on the dedicated synonyms page
+const synonymGroupSelector = "#synonyme .enumeration__item, .content-section .vignette"
+
+// synonymSenseSelector matches the heading that names the sense of a group.
+// Only direct children of the group container count, so that emphasized
+// synonyms are not taken for a heading.
+const synonymSenseSelector = ".enumeration__title, .vignette__title"
+
+// synonymSectionSelector matches the synonyms of a page without sense groups
+const synonymSectionSelector = "#synonyme"
+
+// SynonymeExtractor extracts synonyms from the entry page and, if the entry
+// links to it, the grouped synonyms from the dedicated synonyms page
 type SynonymeExtractor struct {
 	*BaseExtractor
+	fetcher DocumentFetcher
 }
 
-// NewSynonymeExtractor creates a new SynonymeExtractor
-func NewSynonymeExtractor(doc *goquery.Document) Extractor {
+// NewSynonymeExtractor creates a new SynonymeExtractor.
+// fetcher may be nil, in which case the synonyms page is not loaded.
+func NewSynonymeExtractor(doc *goquery.Document, fetcher DocumentFetcher) Extractor {
 	return &SynonymeExtractor{
 		BaseExtractor: NewBaseExtractor(doc),
+		fetcher:       fetcher,
 	}
 }
 
-// Extract extracts synonyms
+// Extract extracts the flat synonym list and the synonym groups
 func (e *SynonymeExtractor) Extract() interface{} {
+	moreLink := e.extractMoreLink()
+	synonyms := e.extractSynonyms()
+	groups := e.fetchSynonymGroups(moreLink)
+
 	return map[string]interface{}{
-		"synonyms":  e.extractSynonyms(),
-		"more_link": e.extractMoreLink(),
+		"synonyms":  mergeSynonyms(synonyms, groups),
+		"groups":    groups,
+		"more_link": moreLink,
 	}
 }
 
@@ -35,44 +53,13 @@ func (e *SynonymeExtractor) extractSynonyms() []models.Synonym {
 	var synonyms []models.Synonym
 
 	e.Doc.Find("#synonyme ul li").Each(func(i int, s *goquery.Selection) {
-		// Get the text and split by commas
-		text := e.CleanText(s.Text())
-		parts := strings.Split(text, ",")
-
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
-
-			// Check if this part has a link
-			var href string
-			s.Find("a").Each(func(j int, a *goquery.Selection) {
-				if e.CleanText(a.Text()) == part {
-					if h, exists := a.Attr("href"); exists {
-						href = h
-					}
-				}
-			})
-
-			synonyms = append(synonyms, models.Synonym{
-				Text: part,
-				Link: href,
-			})
-		}
+		synonyms = append(synonyms, e.parseSynonymList(s, e.CleanText(s.Text()))...)
 	})
 
-	// If we have a "more link", fetch additional synonyms
-	moreLink := e.extractMoreLink()
-	if moreLink != "" {
-		additionalSynonyms := e.fetchAdditionalSynonyms(moreLink)
-		synonyms = append(synonyms, additionalSynonyms...)
-	}
-
 	return synonyms
 }
 
-// extractMoreLink extracts the "more link" if available
+// extractMoreLink extracts the link to the dedicated synonyms page if available
 func (e *SynonymeExtractor) extractMoreLink() string {
 	var moreLink string
 	e.Doc.Find("#synonyme .more__link").Each(func(i int, s *goquery.Selection) {
@@ -83,50 +70,156 @@ func (e *SynonymeExtractor) extractMoreLink() string {
 	return moreLink
 }
 
-// fetchAdditionalSynonyms fetches additional synonyms from the provided link
-func (e *SynonymeExtractor) fetchAdditionalSynonyms(link string) []models.Synonym {
-	var additionalSynonyms []models.Synonym
-
-	// Ensure the link is absolute
-	if !strings.HasPrefix(link, "http") {
-		link = "https://www.duden.de" + link
+// fetchSynonymGroups loads the synonyms page and extracts one group per sense
+func (e *SynonymeExtractor) fetchSynonymGroups(link string) []models.SynonymGroup {
+	if link == "" || e.fetcher == nil {
+		return nil
 	}
 
-	// Create a client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	doc, err := e.fetcher.FetchDocument(link)
+	if err != nil {
+		return nil
 	}
 
-	// Make the request
-	resp, err := client.Get(link)
-	if err != nil {
-		return additionalSynonyms
+	var groups []models.SynonymGroup
+	doc.Find(synonymGroupSelector).Each(func(i int, s *goquery.Selection) {
+		// Only start from groups that are not nested inside another group
+		if s.Parent().Closest(synonymGroupSelector).Length() > 0 {
+			return
+		}
+
+		if group := e.extractGroup(s); len(group.Synonyms) > 0 {
+			groups = append(groups, group)
+		}
+	})
+
+	// Words with a single sense list their synonyms without a sense heading
+	if len(groups) == 0 {
+		if group := e.extractGroup(doc.Find(synonymSectionSelector).First()); len(group.Synonyms) > 0 {
+			groups = append(groups, group)
+		}
 	}
-	defer resp.Body.Close()
 
-	// Parse the response
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return additionalSynonyms
+	return groups
+}
+
+// extractGroup extracts the sense heading and synonyms of a single group
+func (e *SynonymeExtractor) extractGroup(s *goquery.Selection) models.SynonymGroup {
+	heading := s.ChildrenFiltered(synonymSenseSelector).First()
+	group := models.SynonymGroup{
+		Sense: strings.TrimSuffix(e.CleanText(heading.Text()), ":"),
 	}
 
-	// Extract synonyms from the additional page
-	doc.Find(".content-section .vignette__content").Each(func(i int, s *goquery.Selection) {
+	items := s.Find("li")
+	if items.Length() == 0 {
+		// The synonyms are a comma-separated paragraph after the heading
 		text := e.CleanText(s.Text())
-		parts := strings.Split(text, ",")
+		if headingText := e.CleanText(heading.Text()); headingText != "" {
+			text = strings.TrimSpace(strings.TrimPrefix(text, headingText))
+		}
+		group.Synonyms = e.parseSynonymList(s, text)
+		return group
+	}
 
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
+	items.Each(func(i int, li *goquery.Selection) {
+		group.Synonyms = append(group.Synonyms, e.parseSynonymList(li, e.CleanText(li.Text()))...)
+	})
+	return group
+}
 
-			additionalSynonyms = append(additionalSynonyms, models.Synonym{
-				Text: part,
-				Link: "",
-			})
+// parseSynonymList splits "Bude (umgangssprachlich), Gebäude, Heim" into synonyms
+// with their labels and takes links from the anchors inside container
+func (e *SynonymeExtractor) parseSynonymList(container *goquery.Selection, text string) []models.Synonym {
+	links := make(map[string]string)
+	container.Find("a").Each(func(i int, a *goquery.Selection) {
+		if href, exists := a.Attr("href"); exists {
+			links[e.CleanText(a.Text())] = href
 		}
 	})
 
-	return additionalSynonyms
+	var synonyms []models.Synonym
+	for _, part := range splitTopLevel(text, ',', ';') {
+		synonym := parseSynonym(part)
+		if synonym.Text == "" {
+			continue
+		}
+		synonym.Link = links[synonym.Text]
+		synonyms = append(synonyms, synonym)
+	}
+	return synonyms
+}
+
+// parseSynonym separates a synonym from its label annotations, which Duden writes
+// before ("(gehoben) Heim") or after ("Bude (umgangssprachlich)") the word
+func parseSynonym(raw string) models.Synonym {
+	text, labels := parseLabels(raw)
+
+	var sb strings.Builder
+	last := 0
+	for _, group := range findParentheticals(text) {
+		found, _ := splitAnnotation(text[group.start+1 : group.end-1])
+		labels = mergeLabels(labels, found)
+		sb.WriteString(text[last:group.start])
+		last = group.end
+	}
+	sb.WriteString(text[last:])
+
+	return models.Synonym{
+		Text:   normalizeSpacing(sb.String()),
+		Labels: labels,
+	}
+}
+
+// splitTopLevel splits text at separators that are not inside parentheses
+func splitTopLevel(text string, separators ...rune) []string {
+	var parts []string
+	depth, start := 0, 0
+
+	for i, r := range text {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case depth == 0 && strings.ContainsRune(string(separators), r):
+			parts = append(parts, strings.TrimSpace(text[start:i]))
+			start = i + len(string(r))
+		}
+	}
+	parts = append(parts, strings.TrimSpace(text[start:]))
+
+	return parts
+}
+
+// mergeSynonyms adds the synonyms from the groups to the main-page synonyms,
+// skipping duplicates and filling in links and labels the main page lacks
+func mergeSynonyms(synonyms []models.Synonym, groups []models.SynonymGroup) []models.Synonym {
+	index := make(map[string]int, len(synonyms))
+	var merged []models.Synonym
+
+	add := func(synonym models.Synonym) {
+		key := strings.ToLower(synonym.Text)
+		if i, exists := index[key]; exists {
+			if merged[i].Link == "" {
+				merged[i].Link = synonym.Link
+			}
+			if merged[i].Labels.IsEmpty() {
+				merged[i].Labels = synonym.Labels
+			}
+			return
+		}
+		index[key] = len(merged)
+		merged = append(merged, synonym)
+	}
+
+	for _, synonym := range synonyms {
+		add(synonym)
+	}
+	for _, group := range groups {
+		for _, synonym := range group.Synonyms {
+			add(synonym)
+		}
+	}
+
+	return merged
 }
@@ -3,23 +3,86 @@ package elasticsearch
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/filter"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 )
 
-var esClient *elasticsearch.Client
+var (
+	esClient     *elasticsearch.Client
+	connectMutex sync.Mutex
+)
+
+// breaker skips Elasticsearch while it is unavailable
+var breaker = health.NewBreaker("elasticsearch", isOutage)
+
+// statusError is an error response of Elasticsearch
+type statusError struct {
+	status int
+	body   string
+}
+
+// Error implements error
+func (e *statusError) Error() string {
+	return fmt.Sprintf("elasticsearch error: %d %s", e.status, e.body)
+}
+
+// responseError returns the error of a failed response
+func responseError(res *esapi.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return &statusError{status: res.StatusCode, body: strings.TrimSpace(string(body))}
+}
+
+// isOutage reports whether err means Elasticsearch cannot be reached or is overloaded
+func isOutage(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status >= 500 || statusErr.status == 429
+	}
+	return health.IsNetworkError(err)
+}
+
+// ConnectElasticsearch returns the Elasticsearch client, connecting on first
+// use. While Elasticsearch is unavailable it returns an error without trying
+// to connect; connecting is retried after a cooldown.
+func ConnectElasticsearch() (*elasticsearch.Client, error) {
+	connectMutex.Lock()
+	defer connectMutex.Unlock()
 
-// ConnectElasticsearch establishes a connection to Elasticsearch
-func ConnectElasticsearch() *elasticsearch.Client {
 	if esClient != nil {
-		return esClient
+		return esClient, nil
+	}
+	if err := breaker.Allow(); err != nil {
+		return nil, err
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, _, err := connect(ctx)
+	if err != nil {
+		return nil, breaker.Trip(err)
+	}
+
+	esClient = client
+	breaker.SetConnected()
+	return client, nil
+}
+
+// connect creates a client, checks the connection and returns the server version
+func connect(ctx context.Context) (*elasticsearch.Client, string, error) {
 	// Get Elasticsearch URI from environment variable or use default
 	esURI := os.Getenv("ELASTICSEARCH_URI")
 	if esURI == "" {
@@ -32,28 +95,218 @@ func ConnectElasticsearch() *elasticsearch.Client {
 
 	client, err := elasticsearch.NewClient(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to Elasticsearch: %v", err)
+		return nil, "", err
 	}
 
 	// Test the connection
-	res, err := client.Info()
+	version, err := serverVersion(ctx, client)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, version, nil
+}
+
+// serverVersion requests the cluster info and returns the version number
+func serverVersion(ctx context.Context, client *elasticsearch.Client) (string, error) {
+	res, err := client.Info(client.Info.WithContext(ctx))
 	if err != nil {
-		log.Fatalf("Failed to get Elasticsearch info: %v", err)
+		return "", err
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		log.Fatalf("Elasticsearch error: %s", res.String())
+		return "", responseError(res)
 	}
 
-	esClient = client
-	return client
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&info); err != nil {
+		return "", err
+	}
+	return info.Version.Number, nil
+}
+
+// CheckHealth connects to Elasticsearch, bypassing the circuit breaker, and
+// reports its state, latency and server version
+func CheckHealth() health.Status {
+	return breaker.Probe(func(ctx context.Context) (string, error) {
+		if breaker.Down() {
+			// Try again even if the breaker is open
+			_, version, err := connect(ctx)
+			return version, err
+		}
+		client, err := ConnectElasticsearch()
+		if err != nil {
+			return "", err
+		}
+		return serverVersion(ctx, client)
+	})
 }
 
+// wordsMapping is the settings and mapping of the words index
+const wordsMapping = `{
+	"settings": {
+		"number_of_shards": 1,
+		"number_of_replicas": 0
+	},
+	"mappings": {
+		"properties": {
+			"id": {
+				"type": "keyword"
+			},
+			"lookup_key": {
+				"type": "keyword"
+			},
+			"source_url": {
+				"type": "keyword"
+			},
+			"fetched_at": {
+				"type": "date"
+			},
+			"word_type": {
+				"type": "text",
+				"fields": {
+					"keyword": {
+						"type": "keyword",
+						"ignore_above": 256
+					}
+				}
+			},
+			"frequency": {
+				"type": "text",
+				"fields": {
+					"keyword": {
+						"type": "keyword",
+						"ignore_above": 256
+					}
+				}
+			},
+			"word": {
+				"type": "text",
+				"analyzer": "standard",
+				"fields": {
+					"keyword": {
+						"type": "keyword"
+					}
+				}
+			},
+			"partOfSpeech": {
+				"type": "keyword"
+			},
+			"definitions": {
+				"type": "text",
+				"analyzer": "standard"
+			},
+			"examples": {
+				"type": "text",
+				"analyzer": "standard"
+			},
+			"synonyms": {
+				"type": "nested",
+				"properties": {
+					"text": {
+						"type": "text",
+						"analyzer": "standard"
+					},
+					"register": {
+						"type": "keyword"
+					}
+				}
+			},
+			"synonym_groups": {
+				"properties": {
+					"sense": {
+						"type": "text",
+						"analyzer": "standard"
+					},
+					"synonyms": {
+						"type": "nested",
+						"properties": {
+							"text": {
+								"type": "text",
+								"analyzer": "standard"
+							},
+							"register": {
+								"type": "keyword"
+							}
+						}
+					}
+				}
+			},
+			"antonyms": {
+				"properties": {
+					"text": {
+						"type": "keyword"
+					}
+				}
+			},
+			"collocations": {
+				"properties": {
+					"text": {
+						"type": "text",
+						"analyzer": "standard"
+					},
+					"strength": {
+						"type": "float"
+					}
+				}
+			},
+			"compounds": {
+				"properties": {
+					"text": {
+						"type": "keyword"
+					}
+				}
+			},
+			"word_family": {
+				"properties": {
+					"text": {
+						"type": "keyword"
+					}
+				}
+			},
+			"etymology": {
+				"properties": {
+					"text": {
+						"type": "text",
+						"analyzer": "standard"
+					},
+					"sources": {
+						"type": "nested",
+						"properties": {
+							"word": {
+								"type": "keyword"
+							},
+							"language": {
+								"type": "keyword"
+							},
+							"link": {
+								"type": "keyword"
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
 // CreateWordsIndex creates the index for words if it doesn't exist
 func CreateWordsIndex() error {
-	client := ConnectElasticsearch()
+	client, err := ConnectElasticsearch()
+	if err != nil {
+		return err
+	}
+	return breaker.Do(func() error {
+		return createWordsIndex(client)
+	})
+}
 
+// createWordsIndex creates the words index with its mapping
+func createWordsIndex(client *elasticsearch.Client) error {
 	// Check if index exists
 	res, err := client.Indices.Exists([]string{"words"})
 	if err != nil {
@@ -65,52 +318,18 @@ func CreateWordsIndex() error {
 	if res.StatusCode == 200 {
 		return nil
 	}
+	if res.StatusCode != 404 {
+		return responseError(res)
+	}
 
-	// Define index mapping
-	mapping := `{
-		"settings": {
-			"number_of_shards": 1,
-			"number_of_replicas": 0
-		},
-		"mappings": {
-			"properties": {
-				"word": {
-					"type": "text",
-					"analyzer": "standard",
-					"fields": {
-						"keyword": {
-							"type": "keyword"
-						}
-					}
-				},
-				"partOfSpeech": {
-					"type": "keyword"
-				},
-				"definitions": {
-					"type": "text",
-					"analyzer": "standard"
-				},
-				"examples": {
-					"type": "text",
-					"analyzer": "standard"
-				},
-				"synonyms": {
-					"type": "nested",
-					"properties": {
-						"text": {
-							"type": "text",
-							"analyzer": "standard"
-						}
-					}
-				}
-			}
-		}
-	}`
+	return createIndex(client, "words")
+}
 
-	// Create index
-	res, err = client.Indices.Create(
-		"words",
-		client.Indices.Create.WithBody(strings.NewReader(mapping)),
+// createIndex creates an index with the words mapping
+func createIndex(client *elasticsearch.Client, name string) error {
+	res, err := client.Indices.Create(
+		name,
+		client.Indices.Create.WithBody(strings.NewReader(wordsMapping)),
 	)
 	if err != nil {
 		return err
@@ -118,15 +337,202 @@ func CreateWordsIndex() error {
 	defer res.Body.Close()
 
 	if res.IsError() {
+		err := responseError(res)
+		if strings.Contains(err.Error(), "resource_already_exists_exception") {
+			// Created concurrently
+			return nil
+		}
 		return err
 	}
 
 	return nil
 }
 
-// IndexWord indexes a word in Elasticsearch
+// Outcomes of UpdateWordsMapping
+const (
+	MappingCurrent   = "current"   // the index has the current mapping
+	MappingUpdated   = "updated"   // missing fields were added in place
+	MappingReindexed = "reindexed" // the index was rebuilt with the current mapping
+)
+
+// reindexTemp holds the documents while the words index is rebuilt
+const reindexTemp = "words_reindex"
+
+// mappingField is a field of an index mapping
+type mappingField struct {
+	Type       string                  `json:"type"`
+	Properties map[string]mappingField `json:"properties"`
+}
+
+// kind returns the field type; fields with properties default to objects
+func (f mappingField) kind() string {
+	if f.Type == "" {
+		return "object"
+	}
+	return f.Type
+}
+
+// UpdateWordsMapping brings the mapping of the words index up to date. Missing
+// fields are added in place. Fields that are mapped differently cannot be
+// changed, e.g. etymology sources mapped dynamically as objects before they
+// were nested, so the documents are then copied to a temporary index and back
+// into a recreated words index; searches miss them until the copy is done.
+// With dryRun set, only the outcome is reported.
+func UpdateWordsMapping(dryRun bool) (string, error) {
+	client, err := ConnectElasticsearch()
+	if err != nil {
+		return "", err
+	}
+
+	var wanted struct {
+		Mappings mappingField `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(wordsMapping), &wanted); err != nil {
+		return "", err
+	}
+
+	outcome := MappingCurrent
+	err = breaker.Do(func() error {
+		current, exists, err := currentMapping(client)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			// Created with the current mapping on first write
+			return nil
+		}
+
+		missing, conflicts := compareMapping(wanted.Mappings.Properties, current.Properties)
+		switch {
+		case len(conflicts) > 0:
+			outcome = MappingReindexed
+			if dryRun {
+				return nil
+			}
+			log.Printf("Rebuilding Elasticsearch index for changed fields: %s", strings.Join(conflicts, ", "))
+			return rebuildWordsIndex(client)
+		case missing:
+			outcome = MappingUpdated
+			if dryRun {
+				return nil
+			}
+			properties, err := json.Marshal(map[string]interface{}{"properties": wanted.Mappings.Properties})
+			if err != nil {
+				return err
+			}
+			return checkResponse(client.Indices.PutMapping(strings.NewReader(string(properties)), client.Indices.PutMapping.WithIndex("words")))
+		}
+		return nil
+	})
+	return outcome, err
+}
+
+// currentMapping returns the mapping of the words index and whether it exists
+func currentMapping(client *elasticsearch.Client) (mappingField, bool, error) {
+	res, err := client.Indices.GetMapping(client.Indices.GetMapping.WithIndex("words"))
+	if err != nil {
+		return mappingField{}, false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return mappingField{}, false, nil
+	}
+	if res.IsError() {
+		return mappingField{}, false, responseError(res)
+	}
+
+	var indices map[string]struct {
+		Mappings mappingField `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indices); err != nil {
+		return mappingField{}, false, err
+	}
+	for _, index := range indices {
+		return index.Mappings, true, nil
+	}
+	return mappingField{}, false, nil
+}
+
+// compareMapping reports whether fields of wanted are missing from current and
+// lists the fields whose type differs
+func compareMapping(wanted, current map[string]mappingField) (bool, []string) {
+	missing := false
+	var conflicts []string
+	for name, field := range wanted {
+		existing, exists := current[name]
+		if !exists {
+			missing = true
+			continue
+		}
+		if field.kind() != existing.kind() {
+			conflicts = append(conflicts, name)
+			continue
+		}
+		subMissing, subConflicts := compareMapping(field.Properties, existing.Properties)
+		missing = missing || subMissing
+		for _, conflict := range subConflicts {
+			conflicts = append(conflicts, name+"."+conflict)
+		}
+	}
+	sort.Strings(conflicts)
+	return missing, conflicts
+}
+
+// rebuildWordsIndex recreates the words index with the current mapping,
+// keeping its documents
+func rebuildWordsIndex(client *elasticsearch.Client) error {
+	// A temporary index left by an interrupted run still holds the documents
+	if err := checkResponse(client.Indices.Delete([]string{reindexTemp}, client.Indices.Delete.WithIgnoreUnavailable(true))); err != nil {
+		return err
+	}
+	if err := createIndex(client, reindexTemp); err != nil {
+		return err
+	}
+	if err := copyIndex(client, "words", reindexTemp); err != nil {
+		return err
+	}
+	if err := checkResponse(client.Indices.Delete([]string{"words"})); err != nil {
+		return err
+	}
+	if err := createIndex(client, "words"); err != nil {
+		return err
+	}
+	if err := copyIndex(client, reindexTemp, "words"); err != nil {
+		return err
+	}
+	return checkResponse(client.Indices.Delete([]string{reindexTemp}))
+}
+
+// copyIndex copies all documents of one index into another and waits until they are searchable
+func copyIndex(client *elasticsearch.Client, source, dest string) error {
+	body := fmt.Sprintf(`{"source": {"index": %q}, "dest": {"index": %q}}`, source, dest)
+	return checkResponse(client.Reindex(
+		strings.NewReader(body),
+		client.Reindex.WithWaitForCompletion(true),
+		client.Reindex.WithRefresh(true),
+	))
+}
+
+// checkResponse closes a response and returns its error, if any
+func checkResponse(res *esapi.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return responseError(res)
+	}
+	return nil
+}
+
+// IndexWord indexes a word in Elasticsearch under its entry ID
 func IndexWord(word *models.Word) error {
-	client := ConnectElasticsearch()
+	client, err := ConnectElasticsearch()
+	if err != nil {
+		return err
+	}
 
 	// Ensure index exists
 	if err := CreateWordsIndex(); err != nil {
@@ -142,27 +548,31 @@ func IndexWord(word *models.Word) error {
 	// Index document
 	req := esapi.IndexRequest{
 		Index:      "words",
-		DocumentID: word.Word,
+		DocumentID: word.ID,
 		Body:       strings.NewReader(string(wordJSON)),
 		Refresh:    "true",
 	}
 
-	res, err := req.Do(context.Background(), client)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.IsError() {
-		return err
-	}
+	return breaker.Do(func() error {
+		res, err := req.Do(context.Background(), client)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
 
-	return nil
+		if res.IsError() {
+			return responseError(res)
+		}
+		return nil
+	})
 }
 
 // SearchWords searches for words in Elasticsearch
 func SearchWords(query string) ([]models.Word, error) {
-	client := ConnectElasticsearch()
+	client, err := ConnectElasticsearch()
+	if err != nil {
+		return nil, err
+	}
 
 	// Build search query
 	searchQuery := `{
@@ -175,20 +585,25 @@ func SearchWords(query string) ([]models.Word, error) {
 	}`
 
 	// Perform search
-	res, err := client.Search(
-		client.Search.WithIndex("words"),
-		client.Search.WithBody(strings.NewReader(searchQuery)),
-		client.Search.WithSize(10),
-	)
+	var res *esapi.Response
+	err = breaker.Do(func() error {
+		var err error
+		res, err = client.Search(
+			client.Search.WithIndex("words"),
+			client.Search.WithBody(strings.NewReader(searchQuery)),
+			client.Search.WithSize(10),
+		)
+		if err == nil && res.IsError() {
+			err = responseError(res)
+			res.Body.Close()
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	if res.IsError() {
-		return nil, err
-	}
-
 	// Parse response
 	var result map[string]interface{}
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
@@ -216,3 +631,200 @@ func SearchWords(query string) ([]models.Word, error) {
 
 	return words, nil
 }
+
+// SearchByEtymologyLanguage finds words with a source word from the given language.
+// Qualified languages are included, so "französisch" also finds "altfranzösisch".
+func SearchByEtymologyLanguage(language string, size int) ([]models.Word, error) {
+	client, err := ConnectElasticsearch()
+	if err != nil {
+		return nil, err
+	}
+
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"nested": map[string]interface{}{
+				"path": "etymology.sources",
+				"query": map[string]interface{}{
+					"wildcard": map[string]interface{}{
+						"etymology.sources.language": "*" + strings.ToLower(language),
+					},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []scrollHit
+	err = breaker.Do(func() error {
+		res, err := client.Search(
+			client.Search.WithIndex("words"),
+			client.Search.WithBody(strings.NewReader(string(body))),
+			client.Search.WithSize(size),
+		)
+		if err != nil {
+			return err
+		}
+		_, hits, err = decodeScrollPage(res)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	words := make([]models.Word, 0, len(hits))
+	for _, hit := range hits {
+		var word models.Word
+		if err := json.Unmarshal(hit.Source, &word); err != nil {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return words, nil
+}
+
+// ForEachWord scrolls through every indexed word and passes it to fn
+func ForEachWord(fn func(word *models.Word) error) error {
+	return ForEachWordMatching(filter.Words{}, fn)
+}
+
+// ForEachWordMatching streams the indexed words selected by f through fn
+func ForEachWordMatching(f filter.Words, fn func(word *models.Word) error) error {
+	client, err := ConnectElasticsearch()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]interface{}{"query": wordsQuery(f)})
+	if err != nil {
+		return err
+	}
+
+	return breaker.Do(func() error {
+		res, err := client.Search(
+			client.Search.WithIndex("words"),
+			client.Search.WithBody(strings.NewReader(string(body))),
+			client.Search.WithSize(500),
+			client.Search.WithScroll(time.Minute),
+		)
+		if err != nil {
+			return err
+		}
+
+		for {
+			scrollID, hits, err := decodeScrollPage(res)
+			if err != nil {
+				return err
+			}
+			if len(hits) == 0 {
+				return nil
+			}
+
+			for _, hit := range hits {
+				var word models.Word
+				if err := json.Unmarshal(hit.Source, &word); err != nil {
+					return err
+				}
+				if err := fn(&word); err != nil {
+					return err
+				}
+			}
+
+			res, err = client.Scroll(
+				client.Scroll.WithScrollID(scrollID),
+				client.Scroll.WithScroll(time.Minute),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// wildcardEscaper escapes the wildcard characters of a wildcard query
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+// wordsQuery translates a word filter into a query. Word types and frequencies
+// are matched case-insensitively on their keyword fields.
+func wordsQuery(f filter.Words) map[string]interface{} {
+	anyOf := func(field, prefix, suffix string, values []string) map[string]interface{} {
+		var should []interface{}
+		for _, value := range values {
+			should = append(should, map[string]interface{}{
+				"wildcard": map[string]interface{}{
+					field: map[string]interface{}{
+						"value":            prefix + wildcardEscaper.Replace(value) + suffix,
+						"case_insensitive": true,
+					},
+				},
+			})
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{"should": should, "minimum_should_match": 1},
+		}
+	}
+
+	var filters []interface{}
+	if len(f.WordTypes) > 0 {
+		filters = append(filters, anyOf("word_type.keyword", "*", "*", f.WordTypes))
+	}
+	if len(f.Frequencies) > 0 {
+		filters = append(filters, anyOf("frequency.keyword", "", "", f.Frequencies))
+	}
+
+	fetched := map[string]interface{}{}
+	if !f.FetchedAfter.IsZero() {
+		fetched["gte"] = f.FetchedAfter.UTC().Format(time.RFC3339Nano)
+	}
+	if !f.FetchedBefore.IsZero() {
+		fetched["lt"] = f.FetchedBefore.UTC().Format(time.RFC3339Nano)
+		// Older documents have a zero fetch time instead of none
+		fetched["gt"] = time.Time{}.Format(time.RFC3339Nano)
+	}
+	if len(fetched) > 0 {
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{"fetched_at": fetched},
+		})
+	}
+
+	if len(filters) == 0 {
+		return map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{"filter": filters},
+	}
+}
+
+// scrollHit is a single search hit with its raw source document
+type scrollHit struct {
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// decodeScrollPage decodes one page of search hits and closes the response
+func decodeScrollPage(res *esapi.Response) (string, []scrollHit, error) {
+	defer res.Body.Close()
+
+	if res.IsError() {
+		if res.StatusCode == 404 {
+			// The index has not been created yet
+			return "", nil, nil
+		}
+		return "", nil, responseError(res)
+	}
+
+	var page struct {
+		ScrollID string `json:"_scroll_id"`
+		Hits     struct {
+			Hits []scrollHit `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return "", nil, err
+	}
+
+	return page.ScrollID, page.Hits.Hits, nil
+}
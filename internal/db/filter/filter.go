@@ -0,0 +1,72 @@
+// File: internal/db/filter/filter.go
+
+// Package filter describes a selection of stored words. MongoDB, PostgreSQL
+// and Elasticsearch translate it into their queries; stores that cannot query
+// the fields of a word check each word with Matches.
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// Words selects stored words. Empty fields match every word; words without a
+// fetch time never match a date.
+type Words struct {
+	WordTypes     []string  // word type labels, matched case-insensitively as substrings ("verb" matches "schwaches Verb")
+	Frequencies   []string  // Duden frequencies, matched case-insensitively
+	FetchedAfter  time.Time // words fetched at or after this time
+	FetchedBefore time.Time // words fetched before this time
+}
+
+// Matches reports whether a word passes the filter
+func (f Words) Matches(word *models.Word) bool {
+	if len(f.WordTypes) > 0 && !matchesWordType(word.WordType, f.WordTypes) {
+		return false
+	}
+	if len(f.Frequencies) > 0 && !containsFold(f.Frequencies, word.Frequency) {
+		return false
+	}
+	if !f.FetchedAfter.IsZero() && (word.FetchedAt.IsZero() || word.FetchedAt.Before(f.FetchedAfter)) {
+		return false
+	}
+	if !f.FetchedBefore.IsZero() && (word.FetchedAt.IsZero() || !word.FetchedAt.Before(f.FetchedBefore)) {
+		return false
+	}
+	return true
+}
+
+// Alternatives returns a regular expression matching any of the values literally
+func Alternatives(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// matchesWordType reports whether any label contains any of the wanted types
+func matchesWordType(labels, wanted []string) bool {
+	for _, label := range labels {
+		label = strings.ToLower(label)
+		for _, wordType := range wanted {
+			if strings.Contains(label, strings.ToLower(wordType)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+// File: internal/db/health/health.go
+
+// Package health tracks the availability of the databases. Every backend
+// guards its operations with a Breaker: after repeated outages it stops
+// calling the database, so commands skip it instead of waiting for timeouts,
+// and lets a single call through now and then to reconnect.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+)
+
+// State is the availability of a backend
+type State string
+
+// Backend states
+const (
+	StateUnknown State = "unknown" // not used yet
+	StateUp      State = "up"
+	StateDown    State = "down"
+)
+
+// ErrUnavailable is matched by errors returned for backends that are down
+var ErrUnavailable = errors.New("backend unavailable")
+
+// UnavailableError is returned instead of calling a backend that is down
+type UnavailableError struct {
+	Backend string
+	Cause   error
+}
+
+// Error implements error
+func (e *UnavailableError) Error() string {
+	if e.Cause == nil {
+		return fmt.Sprintf("%s is unavailable", e.Backend)
+	}
+	return fmt.Sprintf("%s is unavailable: %v", e.Backend, e.Cause)
+}
+
+// Is makes UnavailableError match ErrUnavailable
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// Unwrap returns the error that made the backend unavailable
+func (e *UnavailableError) Unwrap() error {
+	return e.Cause
+}
+
+// Breaker settings
+const (
+	failureThreshold = 3                // consecutive outages before a connected backend is skipped
+	initialCooldown  = 5 * time.Second  // wait before the first reconnect attempt
+	maxCooldown      = 60 * time.Second // longest wait between reconnect attempts
+)
+
+// Breaker is a circuit breaker for one backend
+type Breaker struct {
+	name     string
+	isOutage func(err error) bool
+
+	mutex     sync.Mutex
+	state     State
+	failures  int
+	lastErr   error
+	since     time.Time // time of the last state change
+	cooldown  time.Duration
+	retryAt   time.Time
+	probing   bool // a reconnect attempt is in progress
+	connected bool
+}
+
+// NewBreaker creates and registers the breaker of a backend. isOutage tells
+// outages (network errors, timeouts) apart from errors of single operations,
+// such as a missing document, which do not affect availability.
+func NewBreaker(name string, isOutage func(err error) bool) *Breaker {
+	b := &Breaker{name: name, isOutage: isOutage, state: StateUnknown, cooldown: initialCooldown}
+	registryMutex.Lock()
+	registry[name] = b
+	registryMutex.Unlock()
+	return b
+}
+
+// Name returns the backend name
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow returns an UnavailableError while the backend is down and no
+// reconnect attempt is due. When one is due, a single caller is let through.
+func (b *Breaker) Allow() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state != StateDown {
+		return nil
+	}
+	if b.probing || time.Now().Before(b.retryAt) {
+		return &UnavailableError{Backend: b.name, Cause: b.lastErr}
+	}
+	b.probing = true
+	return nil
+}
+
+// Record records the outcome of an operation and returns err. Outages count
+// towards opening the breaker; any other result shows the backend is up.
+func (b *Breaker) Record(err error) error {
+	if err != nil && b.isOutage(err) {
+		b.fail(err, false)
+	} else {
+		b.succeed()
+	}
+	return err
+}
+
+// Trip marks the backend down immediately, e.g. when connecting fails, and
+// returns err as an UnavailableError
+func (b *Breaker) Trip(err error) error {
+	b.fail(err, true)
+	return &UnavailableError{Backend: b.name, Cause: err}
+}
+
+// Do runs fn unless the backend is down and records its outcome
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	return b.Record(fn())
+}
+
+// Down reports whether the breaker is open
+func (b *Breaker) Down() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state == StateDown
+}
+
+// Connected reports whether the backend was connected successfully
+func (b *Breaker) Connected() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.connected
+}
+
+// SetConnected records that the client of the backend was set up
+func (b *Breaker) SetConnected() {
+	b.mutex.Lock()
+	b.connected = true
+	b.mutex.Unlock()
+	b.succeed()
+}
+
+// succeed closes the breaker
+func (b *Breaker) succeed() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == StateDown {
+		logger.Info("Database available again", logger.F("backend", b.name))
+	}
+	if b.state != StateUp {
+		b.since = time.Now()
+	}
+	b.state = StateUp
+	b.failures = 0
+	b.lastErr = nil
+	b.probing = false
+	b.cooldown = initialCooldown
+}
+
+// fail counts an outage and opens the breaker at the threshold or when tripped
+func (b *Breaker) fail(err error, trip bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures++
+	b.lastErr = err
+	if b.state == StateDown {
+		// A reconnect attempt failed; wait longer before the next one
+		b.probing = false
+		b.cooldown *= 2
+		if b.cooldown > maxCooldown {
+			b.cooldown = maxCooldown
+		}
+		b.retryAt = time.Now().Add(b.cooldown)
+		return
+	}
+	if !trip && b.failures < failureThreshold {
+		return
+	}
+
+	b.state = StateDown
+	b.since = time.Now()
+	b.probing = false
+	b.retryAt = time.Now().Add(b.cooldown)
+	logger.Warn("Database unavailable, skipping it until it recovers",
+		logger.F("backend", b.name),
+		logger.F("retry_in", b.cooldown.String()),
+		logger.F("error", err))
+}
+
+// Status is the availability of a backend, as reported by the health command
+type Status struct {
+	Backend  string    `json:"backend"`
+	State    State     `json:"state"`
+	Required bool      `json:"required"`
+	Latency  float64   `json:"latency_ms,omitempty"`
+	Version  string    `json:"version,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// Status returns the current state of the breaker
+func (b *Breaker) Status() Status {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	status := Status{Backend: b.name, State: b.state, Since: b.since}
+	if b.lastErr != nil {
+		status.Error = b.lastErr.Error()
+	}
+	return status
+}
+
+// Probe checks the backend with check, bypassing the breaker, and records the
+// outcome. check returns the server version.
+func (b *Breaker) Probe(check func(ctx context.Context) (string, error)) Status {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	version, err := check(ctx)
+	latency := time.Since(start)
+
+	var unavailable *UnavailableError
+	switch {
+	case errors.As(err, &unavailable):
+		// Already recorded when connecting
+	case err != nil:
+		b.fail(err, true)
+	default:
+		b.succeed()
+	}
+
+	status := b.Status()
+	status.Version = version
+	if err == nil {
+		status.Latency = float64(latency.Microseconds()) / 1000
+	}
+	return status
+}
+
+// registry holds the breakers of all backends by name
+var (
+	registry      = make(map[string]*Breaker)
+	registryMutex sync.Mutex
+)
+
+// Breakers returns the registered breakers sorted by name
+func Breakers() []*Breaker {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	breakers := make([]*Breaker, 0, len(registry))
+	for _, b := range registry {
+		breakers = append(breakers, b)
+	}
+	sort.Slice(breakers, func(i, j int) bool { return breakers[i].name < breakers[j].name })
+	return breakers
+}
+
+// IsNetworkError reports whether err is a connection failure or timeout
+func IsNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
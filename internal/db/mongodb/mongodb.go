@@ -2,85 +2,213 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
+	"sync"
 	"time"
 
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/filter"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
 )
 
 var (
-	client *mongo.Client
-	ctx    context.Context
+	client             *mongo.Client
+	connectMutex       sync.Mutex
+	indexesOnce        sync.Once
+	versionIndexesOnce sync.Once
 )
 
-// ConnectMongoDB establishes a connection to MongoDB
-func ConnectMongoDB() *mongo.Client {
+// breaker skips MongoDB while it is unavailable
+var breaker = health.NewBreaker("mongodb", isOutage)
+
+// isOutage reports whether err means MongoDB cannot be reached
+func isOutage(err error) bool {
+	var selectionErr topology.ServerSelectionError
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err) ||
+		errors.As(err, &selectionErr) || errors.Is(err, mongo.ErrClientDisconnected) ||
+		health.IsNetworkError(err)
+}
+
+// ConnectMongoDB returns the MongoDB client, connecting on first use.
+// While MongoDB is unavailable it returns an error without trying to connect;
+// connecting is retried after a cooldown.
+func ConnectMongoDB() (*mongo.Client, error) {
+	connectMutex.Lock()
+	defer connectMutex.Unlock()
+
 	if client != nil {
-		return client
+		return client, nil
+	}
+	if err := breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := connect(ctx)
+	if err != nil {
+		return nil, breaker.Trip(err)
 	}
 
+	log.Println("Connected to MongoDB")
+	client = c
+	breaker.SetConnected()
+	return client, nil
+}
+
+// connect creates a client and checks the connection
+func connect(ctx context.Context) (*mongo.Client, error) {
 	// Get MongoDB URI from environment variable or use default
 	mongoURI := os.Getenv("MONGODB_URI")
 	if mongoURI == "" {
 		mongoURI = "mongodb://localhost:27017/goden-crawler"
 	}
 
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Connect to MongoDB
-	clientOptions := options.Client().ApplyURI(mongoURI)
-	var err error
-	client, err = mongo.Connect(ctx, clientOptions)
+	clientOptions := options.Client().ApplyURI(mongoURI).SetServerSelectionTimeout(5 * time.Second)
+	c, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		return nil, err
 	}
 
 	// Check the connection
-	err = client.Ping(ctx, nil)
-	if err != nil {
-		log.Fatalf("Failed to ping MongoDB: %v", err)
+	if err := c.Ping(ctx, nil); err != nil {
+		_ = c.Disconnect(context.Background())
+		return nil, err
 	}
+	return c, nil
+}
 
-	log.Println("Connected to MongoDB")
-	return client
+// CheckHealth connects to MongoDB, bypassing the circuit breaker, and reports
+// its state, latency and server version
+func CheckHealth() health.Status {
+	return breaker.Probe(func(ctx context.Context) (string, error) {
+		var c *mongo.Client
+		var err error
+		if breaker.Down() {
+			// Try again even if the breaker is open
+			if c, err = connect(ctx); err == nil {
+				defer c.Disconnect(context.Background())
+			}
+		} else {
+			c, err = ConnectMongoDB()
+		}
+		if err != nil {
+			return "", err
+		}
+
+		var info struct {
+			Version string `bson:"version"`
+		}
+		err = c.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info)
+		return info.Version, err
+	})
+}
+
+// Do runs fn unless MongoDB is unavailable and records whether it could reach MongoDB
+func Do(fn func() error) error {
+	return breaker.Do(fn)
+}
+
+// GetCollection returns a collection of the goden-crawler database
+func GetCollection(name string) (*mongo.Collection, error) {
+	client, err := ConnectMongoDB()
+	if err != nil {
+		return nil, err
+	}
+	return client.Database("goden-crawler").Collection(name), nil
 }
 
 // GetWordsCollection returns the words collection
-func GetWordsCollection() *mongo.Collection {
-	client := ConnectMongoDB()
-	return client.Database("goden-crawler").Collection("words")
+func GetWordsCollection() (*mongo.Collection, error) {
+	collection, err := GetCollection("words")
+	if err != nil {
+		return nil, err
+	}
+
+	indexesOnce.Do(func() {
+		if err := ensureIndexes(collection); err != nil {
+			log.Printf("Failed to create MongoDB indexes: %v", err)
+		}
+	})
+
+	return collection, nil
 }
 
-// SaveWord saves a word to MongoDB
-func SaveWord(word *models.Word) error {
-	collection := GetWordsCollection()
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// ensureIndexes creates the unique entry ID index and the lookup key index
+func ensureIndexes(collection *mongo.Collection) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Use upsert to update if exists or insert if not
-	filter := bson.M{"word": word.Word}
-	update := bson.M{"$set": word}
-	opts := options.Update().SetUpsert(true)
-
-	_, err := collection.UpdateOne(ctx, filter, update, opts)
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"id": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "lookupkey", Value: 1}},
+		},
+	})
 	return err
 }
 
-// GetWord retrieves a word from MongoDB
+// SaveWord saves a word to MongoDB, keyed by its entry ID
+func SaveWord(word *models.Word) error {
+	collection, err := GetWordsCollection()
+	if err != nil {
+		return err
+	}
+
+	return breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Use upsert to update if exists or insert if not.
+		// Documents stored before entries had IDs are matched by word and adopted.
+		filter := bson.M{"$or": []bson.M{
+			{"id": word.ID},
+			{"id": bson.M{"$exists": false}, "word": word.Word},
+		}}
+		update := bson.M{"$set": word}
+		if len(word.Origin) == 0 {
+			// Drop the origin of documents converted by "migrate etymology"
+			update["$unset"] = bson.M{"origin": ""}
+		}
+		opts := options.Update().SetUpsert(true)
+
+		_, err := collection.UpdateOne(ctx, filter, update, opts)
+		return err
+	})
+}
+
+// GetWord retrieves a word from MongoDB by entry ID, falling back to
+// the case- and umlaut-insensitive lookup key
 func GetWord(wordText string) (*models.Word, error) {
-	collection := GetWordsCollection()
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	collection, err := GetWordsCollection()
+	if err != nil {
+		return nil, err
+	}
 
 	var word models.Word
-	filter := bson.M{"word": wordText}
-	err := collection.FindOne(ctx, filter).Decode(&word)
+	err = breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := collection.FindOne(ctx, bson.M{"id": wordText}).Decode(&word)
+		if err == mongo.ErrNoDocuments {
+			filter := bson.M{"lookupkey": models.LookupKey(wordText)}
+			err = collection.FindOne(ctx, filter).Decode(&word)
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +216,148 @@ func GetWord(wordText string) (*models.Word, error) {
 	return &word, nil
 }
 
+// ForEachWord streams every stored word through fn using a cursor.
+// Iteration stops at the first error returned by fn.
+func ForEachWord(fn func(word *models.Word) error) error {
+	return ForEachWordMatching(filter.Words{}, fn)
+}
+
+// ForEachWordMatching streams the stored words selected by f through fn
+func ForEachWordMatching(f filter.Words, fn func(word *models.Word) error) error {
+	collection, err := GetWordsCollection()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var cursor *mongo.Cursor
+	err = breaker.Do(func() error {
+		var err error
+		cursor, err = collection.Find(ctx, wordsQuery(f))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var word models.Word
+		if err := cursor.Decode(&word); err != nil {
+			return err
+		}
+		if err := fn(&word); err != nil {
+			return err
+		}
+	}
+
+	return breaker.Record(cursor.Err())
+}
+
+// wordsQuery translates a word filter into a query on the words collection
+func wordsQuery(f filter.Words) bson.M {
+	query := bson.M{}
+	if len(f.WordTypes) > 0 {
+		// Matches any element of the word type array
+		query["wordtype"] = primitive.Regex{Pattern: filter.Alternatives(f.WordTypes), Options: "i"}
+	}
+	if len(f.Frequencies) > 0 {
+		query["frequency"] = primitive.Regex{Pattern: "^(?:" + filter.Alternatives(f.Frequencies) + ")$", Options: "i"}
+	}
+
+	fetched := bson.M{}
+	if !f.FetchedAfter.IsZero() {
+		fetched["$gte"] = f.FetchedAfter
+	}
+	if !f.FetchedBefore.IsZero() {
+		fetched["$lt"] = f.FetchedBefore
+		// Words without a fetch time have a zero date
+		fetched["$gt"] = time.Time{}
+	}
+	if len(fetched) > 0 {
+		query["fetchedat"] = fetched
+	}
+	return query
+}
+
+// getVersionsCollection returns the collection of previous word versions
+func getVersionsCollection() (*mongo.Collection, error) {
+	collection, err := GetCollection("word_versions")
+	if err != nil {
+		return nil, err
+	}
+
+	versionIndexesOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "wordid", Value: 1}, {Key: "revision", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			log.Printf("Failed to create MongoDB version indexes: %v", err)
+		}
+	})
+
+	return collection, nil
+}
+
+// SaveWordVersion keeps a previous version of a word. Saving the same
+// revision again replaces it.
+func SaveWordVersion(version *models.WordVersion) error {
+	collection, err := getVersionsCollection()
+	if err != nil {
+		return err
+	}
+
+	return breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		filter := bson.M{"wordid": version.WordID, "revision": version.Revision}
+		opts := options.Replace().SetUpsert(true)
+		_, err := collection.ReplaceOne(ctx, filter, version, opts)
+		return err
+	})
+}
+
+// GetWordVersions returns the previous versions of an entry, newest first
+func GetWordVersions(id string) ([]models.WordVersion, error) {
+	collection, err := getVersionsCollection()
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []models.WordVersion
+	err = breaker.Do(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		opts := options.Find().SetSort(bson.D{{Key: "revision", Value: -1}})
+		cursor, err := collection.Find(ctx, bson.M{"wordid": id}, opts)
+		if err != nil {
+			return err
+		}
+		return cursor.All(ctx, &versions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
 // Close closes the MongoDB connection
 func Close() error {
+	connectMutex.Lock()
+	defer connectMutex.Unlock()
+
 	if client != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		return client.Disconnect(ctx)
+		err := client.Disconnect(ctx)
+		client = nil
+		return err
 	}
 	return nil
 }
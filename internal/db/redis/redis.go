@@ -3,22 +3,63 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 	"github.com/go-redis/redis/v8"
 )
 
-var redisClient *redis.Client
+var (
+	redisClient  *redis.Client
+	connectMutex sync.Mutex
+)
+
+// breaker skips Redis while it is unavailable
+var breaker = health.NewBreaker("redis", isOutage)
+
+// isOutage reports whether err means Redis cannot be reached
+func isOutage(err error) bool {
+	return err != redis.Nil && (health.IsNetworkError(err) || errors.Is(err, redis.ErrClosed) ||
+		strings.Contains(err.Error(), "connection pool timeout"))
+}
+
+// ConnectRedis returns the Redis client, connecting on first use. While Redis
+// is unavailable it returns an error without trying to connect; connecting is
+// retried after a cooldown.
+func ConnectRedis() (*redis.Client, error) {
+	connectMutex.Lock()
+	defer connectMutex.Unlock()
 
-// ConnectRedis establishes a connection to Redis
-func ConnectRedis() *redis.Client {
 	if redisClient != nil {
-		return redisClient
+		return redisClient, nil
+	}
+	if err := breaker.Allow(); err != nil {
+		return nil, err
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := connect(ctx)
+	if err != nil {
+		return nil, breaker.Trip(err)
+	}
+
+	log.Println("Connected to Redis")
+	redisClient = client
+	breaker.SetConnected()
+	return client, nil
+}
+
+// connect creates a client and checks the connection
+func connect(ctx context.Context) (*redis.Client, error) {
 	// Get Redis URI from environment variable or use default
 	redisURI := os.Getenv("REDIS_URI")
 	if redisURI == "" {
@@ -27,25 +68,130 @@ func ConnectRedis() *redis.Client {
 
 	opt, err := redis.ParseURL(redisURI)
 	if err != nil {
-		log.Fatalf("Failed to parse Redis URI: %v", err)
+		return nil, fmt.Errorf("failed to parse Redis URI: %w", err)
 	}
 
 	client := redis.NewClient(opt)
-	ctx := context.Background()
-
 	if err := client.Ping(ctx).Err(); err != nil {
-		log.Fatalf("Failed to connect to Redis: %v", err)
+		client.Close()
+		return nil, err
 	}
+	return client, nil
+}
 
-	log.Println("Connected to Redis")
-	redisClient = client
-	return client
+// CheckHealth connects to Redis, bypassing the circuit breaker, and reports
+// its state, latency and server version
+func CheckHealth() health.Status {
+	return breaker.Probe(func(ctx context.Context) (string, error) {
+		var client *redis.Client
+		var err error
+		if breaker.Down() {
+			// Try again even if the breaker is open
+			if client, err = connect(ctx); err == nil {
+				defer client.Close()
+			}
+		} else {
+			client, err = ConnectRedis()
+		}
+		if err != nil {
+			return "", err
+		}
+
+		info, err := client.Info(ctx, "server").Result()
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(info, "\n") {
+			if version, found := strings.CutPrefix(strings.TrimSpace(line), "redis_version:"); found {
+				return version, nil
+			}
+		}
+		return "", nil
+	})
+}
+
+// Cache settings
+const (
+	defaultKeyPrefix  = "goden"
+	defaultKeyVersion = "1"             // bump when the cached JSON changes incompatibly
+	opTimeout         = 5 * time.Second // limit of a single command or pipeline
+	resubscribeDelay  = 5 * time.Second // wait before subscribing again after an error
+	accessBatchSize   = 1000            // entries per pipeline when reading access counts
+)
+
+var (
+	keyspaceOnce sync.Once
+	keyPrefix    string // "<prefix>:" shared by all versions
+	versioned    string // "<prefix>:v<version>:" of cache entries
+)
+
+// loadKeyspace reads REDIS_KEY_PREFIX and REDIS_KEY_VERSION. Instances with
+// different prefixes share a Redis server without seeing each other's entries;
+// changing the version abandons all cached entries, which then expire.
+func loadKeyspace() {
+	keyspaceOnce.Do(func() {
+		prefix, version := defaultKeyPrefix, defaultKeyVersion
+		if value, exists := os.LookupEnv("REDIS_KEY_PREFIX"); exists {
+			prefix = value
+		}
+		if value := os.Getenv("REDIS_KEY_VERSION"); value != "" {
+			version = value
+		}
+		keyPrefix = ""
+		if prefix != "" {
+			keyPrefix = prefix + ":"
+		}
+		versioned = keyPrefix + "v" + version + ":"
+	})
+}
+
+// Do runs fn unless Redis is unavailable and records whether it could reach Redis
+func Do(fn func() error) error {
+	return breaker.Do(fn)
+}
+
+// wordKey returns the cache key of an entry ID
+func wordKey(id string) string {
+	loadKeyspace()
+	return versioned + "word:" + id
+}
+
+// lookupKey returns the key of the set of entry IDs sharing a lookup key
+func lookupKey(key string) string {
+	loadKeyspace()
+	return versioned + "lookup:" + key
+}
+
+// Key returns the name of a key in the namespace of REDIS_KEY_PREFIX, for data
+// other than cached words. It is not versioned.
+func Key(name string) string {
+	loadKeyspace()
+	return keyPrefix + name
+}
+
+// invalidationChannel returns the pub/sub channel of changed words. It is not
+// versioned, so instances running different versions still hear each other.
+func invalidationChannel() string {
+	return Key("invalidate")
 }
 
-// CacheWord caches a word in Redis
+// patternEscaper escapes the glob characters of SCAN patterns
+var patternEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// opContext returns a context limiting a single command or pipeline
+func opContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), opTimeout)
+}
+
+// CacheWord caches a word in Redis under its entry ID and adds the ID
+// to the lookup set of its normalized spelling
 func CacheWord(word *models.Word, ttl time.Duration) error {
-	client := ConnectRedis()
-	ctx := context.Background()
+	client, err := ConnectRedis()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := opContext()
+	defer cancel()
 
 	// Convert word to JSON
 	data, err := json.Marshal(word)
@@ -54,18 +200,38 @@ func CacheWord(word *models.Word, ttl time.Duration) error {
 	}
 
 	// Cache with TTL
-	key := "word:" + word.Word
-	return client.Set(ctx, key, data, ttl).Err()
+	pipe := client.TxPipeline()
+	pipe.Set(ctx, wordKey(word.ID), data, ttl)
+	if word.LookupKey != "" {
+		pipe.SAdd(ctx, lookupKey(word.LookupKey), word.ID)
+		if ttl > 0 {
+			pipe.Expire(ctx, lookupKey(word.LookupKey), ttl)
+		}
+	}
+	_, err = pipe.Exec(ctx)
+	return breaker.Record(err)
 }
 
-// GetCachedWord retrieves a cached word from Redis
+// GetCachedWord retrieves a cached word from Redis by entry ID, falling back to
+// the case- and umlaut-insensitive lookup key
 func GetCachedWord(wordText string) (*models.Word, error) {
-	client := ConnectRedis()
-	ctx := context.Background()
+	client, err := ConnectRedis()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := opContext()
+	defer cancel()
 
 	// Get from cache
-	key := "word:" + wordText
-	data, err := client.Get(ctx, key).Bytes()
+	var data []byte
+	err = breaker.Do(func() error {
+		var err error
+		data, err = client.Get(ctx, wordKey(wordText)).Bytes()
+		if err == redis.Nil {
+			data, err = getByLookupKey(ctx, client, models.LookupKey(wordText))
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -79,19 +245,334 @@ func GetCachedWord(wordText string) (*models.Word, error) {
 	return &word, nil
 }
 
-// DeleteCachedWord removes a word from the cache
-func DeleteCachedWord(wordText string) error {
-	client := ConnectRedis()
+// getByLookupKey returns the first cached entry whose lookup key matches
+func getByLookupKey(ctx context.Context, client *redis.Client, key string) ([]byte, error) {
+	ids, err := client.SMembers(ctx, lookupKey(key)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		data, err := client.Get(ctx, wordKey(id)).Bytes()
+		if err == redis.Nil {
+			// The entry expired before its lookup set
+			continue
+		}
+		return data, err
+	}
+
+	return nil, redis.Nil
+}
+
+// ForEachCachedWord scans all cached words and passes them to fn, reading
+// each page of keys with one MGET
+func ForEachCachedWord(fn func(word *models.Word) error) error {
+	client, err := ConnectRedis()
+	if err != nil {
+		return err
+	}
 	ctx := context.Background()
 
-	key := "word:" + wordText
-	return client.Del(ctx, key).Err()
+	return breaker.Do(func() error {
+		var cursor uint64
+		for {
+			pageCtx, cancel := opContext()
+			keys, next, err := client.Scan(pageCtx, cursor, patternEscaper.Replace(wordKey(""))+"*", 100).Result()
+			var values []interface{}
+			if err == nil && len(keys) > 0 {
+				values, err = client.MGet(pageCtx, keys...).Result()
+			}
+			cancel()
+			if err != nil {
+				return err
+			}
+
+			for _, value := range values {
+				data, ok := value.(string)
+				if !ok {
+					// Key expired between SCAN and MGET
+					continue
+				}
+
+				var word models.Word
+				if err := json.Unmarshal([]byte(data), &word); err != nil {
+					return err
+				}
+				if err := fn(&word); err != nil {
+					return err
+				}
+			}
+
+			if cursor = next; cursor == 0 || ctx.Err() != nil {
+				return nil
+			}
+		}
+	})
+}
+
+// GetCachedWords retrieves many cached words with two round trips: one MGET
+// of the texts as entry IDs and one pipeline resolving the remaining texts by
+// lookup key. Texts that are not cached are missing from the result.
+func GetCachedWords(texts []string) (map[string]*models.Word, error) {
+	client, err := ConnectRedis()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := opContext()
+	defer cancel()
+
+	found := make(map[string]*models.Word, len(texts))
+	err = breaker.Do(func() error {
+		keys := make([]string, len(texts))
+		for i, text := range texts {
+			keys[i] = wordKey(text)
+		}
+		values, err := mgetWords(ctx, client, keys)
+		if err != nil {
+			return err
+		}
+
+		var missing []string
+		for i, text := range texts {
+			if word := values[keys[i]]; word != nil {
+				found[text] = word
+			} else {
+				missing = append(missing, text)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+
+		// Resolve the remaining texts through their lookup sets
+		pipe := client.Pipeline()
+		members := make([]*redis.StringSliceCmd, len(missing))
+		for i, text := range missing {
+			members[i] = pipe.SMembers(ctx, lookupKey(models.LookupKey(text)))
+		}
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return err
+		}
+
+		var idKeys []string
+		for _, cmd := range members {
+			for _, id := range cmd.Val() {
+				idKeys = append(idKeys, wordKey(id))
+			}
+		}
+		if values, err = mgetWords(ctx, client, idKeys); err != nil {
+			return err
+		}
+		for i, text := range missing {
+			for _, id := range members[i].Val() {
+				if word := values[wordKey(id)]; word != nil {
+					found[text] = word
+					break
+				}
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// mgetWords reads and decodes the given keys with one MGET. Missing keys and
+// undecodable entries are left out.
+func mgetWords(ctx context.Context, client *redis.Client, keys []string) (map[string]*models.Word, error) {
+	words := make(map[string]*models.Word, len(keys))
+	if len(keys) == 0 {
+		return words, nil
+	}
+
+	values, err := client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var word models.Word
+		if err := json.Unmarshal([]byte(data), &word); err != nil {
+			continue
+		}
+		words[keys[i]] = &word
+	}
+	return words, nil
+}
+
+// RecacheWord overwrites a cached word while keeping its remaining TTL
+func RecacheWord(word *models.Word) error {
+	return CacheWord(word, redis.KeepTTL)
+}
+
+// DeleteCachedWord removes an entry from the cache by its ID
+func DeleteCachedWord(id string) error {
+	client, err := ConnectRedis()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := opContext()
+	defer cancel()
+
+	return breaker.Do(func() error {
+		return client.Del(ctx, wordKey(id)).Err()
+	})
+}
+
+// accessKey returns the sorted set counting reads per entry ID. It is not
+// versioned, so counts survive a change of the cache format.
+func accessKey() string {
+	return Key("access")
+}
+
+// RecordAccess counts a read of each of the given entries
+func RecordAccess(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	client, err := ConnectRedis()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := opContext()
+	defer cancel()
+
+	return breaker.Do(func() error {
+		pipe := client.Pipeline()
+		for _, id := range ids {
+			pipe.ZIncrBy(ctx, accessKey(), 1, id)
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
+
+// AccessCounts returns how often each of the given entries was read.
+// Entries that were never read are missing.
+func AccessCounts(ids []string) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	client, err := ConnectRedis()
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(ids); start += accessBatchSize {
+		batch := ids[start:min(start+accessBatchSize, len(ids))]
+		err := breaker.Do(func() error {
+			ctx, cancel := opContext()
+			defer cancel()
+
+			pipe := client.Pipeline()
+			scores := make([]*redis.FloatCmd, len(batch))
+			for i, id := range batch {
+				scores[i] = pipe.ZScore(ctx, accessKey(), id)
+			}
+			if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+				return err
+			}
+			for i, score := range scores {
+				if value, err := score.Result(); err == nil {
+					counts[batch[i]] = int64(value)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// Invalidation announces that a stored word changed
+type Invalidation struct {
+	ID     string `json:"id"`
+	Word   string `json:"word"`
+	Origin string `json:"origin"` // instance that saved the word
+}
+
+// PublishInvalidation announces a changed word to all subscribed instances
+func PublishInvalidation(invalidation Invalidation) error {
+	client, err := ConnectRedis()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(invalidation)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := opContext()
+	defer cancel()
+
+	return breaker.Do(func() error {
+		return client.Publish(ctx, invalidationChannel(), data).Err()
+	})
+}
+
+// SubscribeInvalidations passes the invalidations published by all instances,
+// including this one, to fn until ctx is done. While Redis is unavailable it
+// retries subscribing periodically.
+func SubscribeInvalidations(ctx context.Context, fn func(invalidation Invalidation)) {
+	for ctx.Err() == nil {
+		client, err := ConnectRedis()
+		if err == nil {
+			err = receiveInvalidations(ctx, client, fn)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Redis invalidation subscription interrupted: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// receiveInvalidations subscribes once and delivers messages until the
+// subscription ends. The client reconnects dropped subscriptions itself.
+func receiveInvalidations(ctx context.Context, client *redis.Client, fn func(invalidation Invalidation)) error {
+	pubsub := client.Subscribe(ctx, invalidationChannel())
+	defer pubsub.Close()
+
+	// Wait for the confirmation so that failures are reported
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return breaker.Record(err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return errors.New("subscription closed")
+			}
+			var invalidation Invalidation
+			if err := json.Unmarshal([]byte(message.Payload), &invalidation); err != nil {
+				log.Printf("Ignoring malformed invalidation: %v", err)
+				continue
+			}
+			fn(invalidation)
+		}
+	}
 }
 
 // Close closes the Redis connection
 func Close() error {
+	connectMutex.Lock()
+	defer connectMutex.Unlock()
+
 	if redisClient != nil {
-		return redisClient.Close()
+		err := redisClient.Close()
+		redisClient = nil
+		return err
 	}
 	return nil
 }
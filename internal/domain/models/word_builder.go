@@ -13,9 +13,14 @@ type WordBuilder struct {
 	grammar       string
 	meanings      []models.Meaning
 	synonyms      []models.Synonym
+	synonymGroups []models.SynonymGroup
+	antonyms      []models.RelatedWord
+	collocations  []models.Collocation
+	compounds     []models.RelatedWord
+	wordFamily    []models.RelatedWord
 	pronunciation []models.Pronunciation
-	spelling      models.Spelling
-	origin        []models.Origin
+	spelling      *models.Spelling
+	etymology     *models.Etymology
 	funFacts      []string
 }
 
@@ -26,7 +31,6 @@ func NewWordBuilder() *WordBuilder {
 		meanings:      []models.Meaning{},
 		synonyms:      []models.Synonym{},
 		pronunciation: []models.Pronunciation{},
-		origin:        []models.Origin{},
 		funFacts:      []string{},
 	}
 }
@@ -91,6 +95,36 @@ func (b *WordBuilder) AddSynonym(synonym models.Synonym) *WordBuilder {
 	return b
 }
 
+// AddSynonymGroup adds the synonyms of one sense
+func (b *WordBuilder) AddSynonymGroup(group models.SynonymGroup) *WordBuilder {
+	b.synonymGroups = append(b.synonymGroups, group)
+	return b
+}
+
+// WithAntonyms sets the antonyms
+func (b *WordBuilder) WithAntonyms(antonyms []models.RelatedWord) *WordBuilder {
+	b.antonyms = antonyms
+	return b
+}
+
+// WithCollocations sets the typical collocations
+func (b *WordBuilder) WithCollocations(collocations []models.Collocation) *WordBuilder {
+	b.collocations = collocations
+	return b
+}
+
+// WithCompounds sets the compounds
+func (b *WordBuilder) WithCompounds(compounds []models.RelatedWord) *WordBuilder {
+	b.compounds = compounds
+	return b
+}
+
+// WithWordFamily sets the derived words of the word family
+func (b *WordBuilder) WithWordFamily(family []models.RelatedWord) *WordBuilder {
+	b.wordFamily = family
+	return b
+}
+
 // WithPronunciation sets the pronunciation
 func (b *WordBuilder) WithPronunciation(pronunciation []models.Pronunciation) *WordBuilder {
 	b.pronunciation = pronunciation
@@ -105,19 +139,13 @@ func (b *WordBuilder) AddPronunciation(pronunciation models.Pronunciation) *Word
 
 // WithSpelling sets the spelling
 func (b *WordBuilder) WithSpelling(spelling models.Spelling) *WordBuilder {
-	b.spelling = spelling
-	return b
-}
-
-// WithOrigin sets the origin
-func (b *WordBuilder) WithOrigin(origin []models.Origin) *WordBuilder {
-	b.origin = origin
+	b.spelling = &spelling
 	return b
 }
 
-// AddOrigin adds an origin
-func (b *WordBuilder) AddOrigin(origin models.Origin) *WordBuilder {
-	b.origin = append(b.origin, origin)
+// WithEtymology sets the etymology
+func (b *WordBuilder) WithEtymology(etymology *models.Etymology) *WordBuilder {
+	b.etymology = etymology
 	return b
 }
 
@@ -143,9 +171,14 @@ func (b *WordBuilder) Build() *models.Word {
 		Grammar:       b.grammar,
 		Meanings:      b.meanings,
 		Synonyms:      b.synonyms,
+		SynonymGroups: b.synonymGroups,
+		Antonyms:      b.antonyms,
+		Collocations:  b.collocations,
+		Compounds:     b.compounds,
+		WordFamily:    b.wordFamily,
 		Pronunciation: b.pronunciation,
 		Spelling:      b.spelling,
-		Origin:        b.origin,
+		Etymology:     b.etymology,
 		FunFacts:      b.funFacts,
 	}
 }
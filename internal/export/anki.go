@@ -0,0 +1,507 @@
+// File: internal/export/anki.go
+
+package export
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/media"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+
+	// SQLite driver for the collection database inside the package; needs cgo, see CheckAnki
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// maxAnkiExamples limits the examples shown on the back of a card
+const maxAnkiExamples = 5
+
+// ankiFields are the note fields available to card templates as {{Name}}
+var ankiFields = []string{"Word", "Article", "WordType", "Meanings", "Examples", "Plural", "Audio", "Image", "ID"}
+
+// htmlTags strips markup when computing the sort field checksum
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// AnkiTemplate is the note type of exported cards. Front and Back use Anki's
+// template syntax, e.g. "{{Article}} {{Word}}" or "{{#Plural}}…{{/Plural}}".
+// Notes are tied to the note type by Name, so keep it when changing a template
+// to update previously imported cards.
+type AnkiTemplate struct {
+	Name  string
+	Front string
+	Back  string
+	CSS   string
+}
+
+// DefaultAnkiTemplate shows the word with its article on the front and the
+// meanings, plural, examples, audio and image on the back
+func DefaultAnkiTemplate() AnkiTemplate {
+	return AnkiTemplate{
+		Name:  "Goden Crawler Word",
+		Front: `<div class="word">{{#Article}}<span class="article">{{Article}}</span> {{/Article}}{{Word}}</div>`,
+		Back: `{{FrontSide}}
+<hr id="answer">
+{{#WordType}}<div class="type">{{WordType}}</div>{{/WordType}}
+{{#Plural}}<div class="plural">Plural: {{Plural}}</div>{{/Plural}}
+<div class="meanings">{{Meanings}}</div>
+{{#Examples}}<div class="examples">{{Examples}}</div>{{/Examples}}
+{{Audio}}
+{{#Image}}<div class="image">{{Image}}</div>{{/Image}}`,
+		CSS: `.card { font-family: arial; font-size: 20px; text-align: center; color: black; background-color: white; }
+.article { color: #666; }
+.type, .plural { font-size: 16px; color: #555; }
+.meanings, .examples { text-align: left; }
+.examples { font-style: italic; font-size: 16px; }
+.image img { max-width: 90%; }`,
+	}
+}
+
+// AnkiOptions configures an Anki export
+type AnkiOptions struct {
+	DeckName string
+	Template AnkiTemplate
+	// MediaStore provides local audio and image files; without it cards have no media
+	MediaStore *media.Store
+}
+
+// AnkiExporter collects words and writes them as an Anki package (.apkg).
+// Note GUIDs are derived from word IDs, so importing a newer export of the
+// same words updates the existing notes instead of adding duplicates.
+type AnkiExporter struct {
+	options AnkiOptions
+	notes   []ankiNote
+	media   map[string]string // file name in the package -> path on disk
+}
+
+// ankiNote is one exported word
+type ankiNote struct {
+	guid   string
+	fields []string
+	tags   []string
+}
+
+// NewAnkiExporter creates an AnkiExporter
+func NewAnkiExporter(options AnkiOptions) *AnkiExporter {
+	if options.DeckName == "" {
+		options.DeckName = "Goden Crawler"
+	}
+	if options.Template.Name == "" {
+		options.Template.Name = DefaultAnkiTemplate().Name
+	}
+
+	return &AnkiExporter{
+		options: options,
+		media:   make(map[string]string),
+	}
+}
+
+// Add adds a word as a note
+func (e *AnkiExporter) Add(word *models.Word) {
+	word.EnsureIdentity()
+
+	values := map[string]string{
+		"Word":     html.EscapeString(word.Word),
+		"Article":  html.EscapeString(word.Article),
+		"WordType": html.EscapeString(strings.Join(word.WordType, ", ")),
+		"Meanings": meaningsHTML(word.Meanings),
+		"Examples": examplesHTML(word.Meanings),
+		"Plural":   html.EscapeString(word.Plural()),
+		"ID":       html.EscapeString(word.ID),
+	}
+
+	if file := audioFile(word); file != nil {
+		if name := e.addMedia(*file); name != "" {
+			values["Audio"] = fmt.Sprintf("[sound:%s]", name)
+		}
+	}
+	if file := imageFile(word.Meanings); file != nil {
+		if name := e.addMedia(*file); name != "" {
+			values["Image"] = fmt.Sprintf(`<img src="%s">`, name)
+		}
+	}
+
+	fields := make([]string, len(ankiFields))
+	for i, name := range ankiFields {
+		fields[i] = values[name]
+	}
+
+	tags := []string{"goden-crawler"}
+	for _, wordType := range word.WordType {
+		tags = append(tags, strings.ReplaceAll(wordType, " ", "_"))
+	}
+
+	e.notes = append(e.notes, ankiNote{
+		guid:   noteGUID(word.ID),
+		fields: fields,
+		tags:   tags,
+	})
+}
+
+// Len returns the number of notes added
+func (e *AnkiExporter) Len() int {
+	return len(e.notes)
+}
+
+// addMedia registers a stored file for the package and returns its name in
+// the package, or "" when the file is not available locally
+func (e *AnkiExporter) addMedia(file models.MediaFile) string {
+	if e.options.MediaStore == nil {
+		return ""
+	}
+
+	source := e.options.MediaStore.AbsPath(file)
+	if _, err := os.Stat(source); err != nil {
+		return ""
+	}
+
+	// Stored files are named by checksum, which keeps names unique and stable
+	name := path.Base(file.Path)
+	e.media[name] = source
+	return name
+}
+
+// WriteFile writes the package to path
+func (e *AnkiExporter) WriteFile(target string) error {
+	if err := CheckAnki(); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "anki-export-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	collection := filepath.Join(tmpDir, "collection.anki2")
+	if err := e.writeCollection(collection); err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+
+	if err := e.writePackage(out, collection); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// writePackage zips the collection and the media files. Media files are stored
+// as "0", "1", … and the "media" entry maps these numbers to file names.
+func (e *AnkiExporter) writePackage(w io.Writer, collection string) error {
+	archive := zip.NewWriter(w)
+
+	if err := addFileToZip(archive, "collection.anki2", collection); err != nil {
+		return err
+	}
+
+	mediaMap := make(map[string]string)
+	index := 0
+	for name, source := range e.media {
+		entry := strconv.Itoa(index)
+		if err := addFileToZip(archive, entry, source); err != nil {
+			return fmt.Errorf("failed to add media %s: %w", name, err)
+		}
+		mediaMap[entry] = name
+		index++
+	}
+
+	mediaJSON, err := json.Marshal(mediaMap)
+	if err != nil {
+		return err
+	}
+	mediaEntry, err := archive.Create("media")
+	if err != nil {
+		return err
+	}
+	if _, err := mediaEntry.Write(mediaJSON); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// addFileToZip copies a file on disk into the archive
+func addFileToZip(archive *zip.Writer, name, source string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, file)
+	return err
+}
+
+// writeCollection creates the SQLite collection with the note type, the deck,
+// and one note and card per word
+func (e *AnkiExporter) writeCollection(file string) error {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(ankiSchema); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	modelID := stableID("model:" + e.options.Template.Name)
+	deckID := stableID("deck:" + e.options.DeckName)
+
+	noteTypes, err := json.Marshal(map[string]interface{}{
+		strconv.FormatInt(modelID, 10): e.noteType(modelID, deckID, now),
+	})
+	if err != nil {
+		return err
+	}
+	decks, err := json.Marshal(map[string]interface{}{
+		"1":                           ankiDeck(1, "Default", now),
+		strconv.FormatInt(deckID, 10): ankiDeck(deckID, e.options.DeckName, now),
+	})
+	if err != nil {
+		return err
+	}
+	conf, err := json.Marshal(map[string]interface{}{
+		"nextPos": len(e.notes) + 1, "estTimes": true, "activeDecks": []int64{1},
+		"sortType": "noteFld", "timeLim": 0, "sortBackwards": false, "addToCur": true,
+		"curDeck": 1, "newBury": true, "newSpread": 0, "dueCounts": true,
+		"curModel": strconv.FormatInt(modelID, 10), "collapseTime": 1200,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		now.Unix(), now.UnixMilli(), now.UnixMilli(), string(conf), string(noteTypes), string(decks), ankiDeckConfig)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, note := range e.notes {
+		noteID := stableID("note:" + note.guid)
+		sortField := note.fields[0]
+
+		_, err := tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`,
+			noteID, note.guid, modelID, now.Unix(),
+			" "+strings.Join(note.tags, " ")+" ",
+			strings.Join(note.fields, "\x1f"), sortField, fieldChecksum(sortField))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`,
+			stableID("card:"+note.guid), noteID, deckID, now.Unix(), i+1)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// noteType returns the note type ("model") definition with the configured templates
+func (e *AnkiExporter) noteType(modelID, deckID int64, now time.Time) map[string]interface{} {
+	template := e.options.Template
+
+	fields := make([]map[string]interface{}, len(ankiFields))
+	for i, name := range ankiFields {
+		fields[i] = map[string]interface{}{
+			"name": name, "ord": i, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+
+	return map[string]interface{}{
+		"id": modelID, "name": template.Name, "type": 0, "mod": now.Unix(), "usn": -1,
+		"sortf": 0, "did": deckID, "tags": []string{}, "vers": []int{},
+		"flds": fields,
+		"tmpls": []map[string]interface{}{{
+			"name": "Card 1", "ord": 0, "qfmt": template.Front, "afmt": template.Back,
+			"did": nil, "bqfmt": "", "bafmt": "",
+		}},
+		"css":       template.CSS,
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"req":       []interface{}{[]interface{}{0, "any", []int{0}}},
+	}
+}
+
+// ankiDeck returns a deck definition
+func ankiDeck(id int64, name string, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id": id, "name": name, "mod": now.Unix(), "usn": -1, "desc": "",
+		"dyn": 0, "conf": 1, "collapsed": false, "extendNew": 10, "extendRev": 50,
+		"newToday": []int{0, 0}, "revToday": []int{0, 0},
+		"lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+	}
+}
+
+// meaningsHTML renders the meaning tree as nested ordered lists
+func meaningsHTML(meanings []models.Meaning) string {
+	if len(meanings) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<ol>")
+	for _, meaning := range meanings {
+		sb.WriteString("<li>")
+		if labels := labelText(meaning.Labels); labels != "" {
+			sb.WriteString(fmt.Sprintf(`<span class="labels">(%s)</span> `, html.EscapeString(labels)))
+		}
+		sb.WriteString(html.EscapeString(meaning.Text))
+		sb.WriteString(meaningsHTML(meaning.SubMeanings))
+		sb.WriteString("</li>")
+	}
+	sb.WriteString("</ol>")
+	return sb.String()
+}
+
+// examplesHTML lists the first examples of all meanings
+func examplesHTML(meanings []models.Meaning) string {
+	var examples []string
+	var collect func(meanings []models.Meaning)
+	collect = func(meanings []models.Meaning) {
+		for _, meaning := range meanings {
+			for _, example := range meaning.Examples {
+				if len(examples) == maxAnkiExamples {
+					return
+				}
+				examples = append(examples, "<li>"+html.EscapeString(example.Text)+"</li>")
+			}
+			collect(meaning.SubMeanings)
+		}
+	}
+	collect(meanings)
+
+	if len(examples) == 0 {
+		return ""
+	}
+	return "<ul>" + strings.Join(examples, "") + "</ul>"
+}
+
+// labelText joins usage labels for display
+func labelText(labels models.Labels) string {
+	parts := append([]string{}, labels.Register...)
+	parts = append(parts, labels.Domain...)
+	if labels.Figurative {
+		parts = append(parts, "figurative")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// audioFile returns the first locally stored pronunciation
+func audioFile(word *models.Word) *models.MediaFile {
+	for _, pronunciation := range word.Pronunciation {
+		if pronunciation.AudioFile != nil {
+			return pronunciation.AudioFile
+		}
+	}
+	return nil
+}
+
+// imageFile returns the first locally stored image of the meaning tree
+func imageFile(meanings []models.Meaning) *models.MediaFile {
+	for _, meaning := range meanings {
+		if meaning.ImageFile != nil {
+			return meaning.ImageFile
+		}
+		if file := imageFile(meaning.SubMeanings); file != nil {
+			return file
+		}
+	}
+	return nil
+}
+
+// noteGUID derives a note's GUID from the word ID. Anki matches imported
+// notes by GUID, so the same word always updates the same note.
+func noteGUID(id string) string {
+	sum := sha256.Sum256([]byte("goden-crawler:" + id))
+	return hex.EncodeToString(sum[:10])
+}
+
+// stableID derives a positive 53-bit ID from a key, so that note types, decks,
+// notes and cards keep their IDs across exports
+func stableID(key string) int64 {
+	sum := sha256.Sum256([]byte(key))
+	return int64(binary.BigEndian.Uint64(sum[:8]) >> 11)
+}
+
+// fieldChecksum is Anki's duplicate check value: the first 8 hex digits of the
+// SHA-1 of the sort field without markup
+func fieldChecksum(field string) int64 {
+	sum := sha1.Sum([]byte(html.UnescapeString(htmlTags.ReplaceAllString(field, ""))))
+	value, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+	return value
+}
+
+// ankiSchema is the schema of an Anki 2.1 collection (schema version 11)
+const ankiSchema = `
+CREATE TABLE col (
+	id integer primary key, crt integer not null, mod integer not null, scm integer not null,
+	ver integer not null, dty integer not null, usn integer not null, ls integer not null,
+	conf text not null, models text not null, decks text not null, dconf text not null, tags text not null
+);
+CREATE TABLE notes (
+	id integer primary key, guid text not null, mid integer not null, mod integer not null,
+	usn integer not null, tags text not null, flds text not null, sfld integer not null,
+	csum integer not null, flags integer not null, data text not null
+);
+CREATE TABLE cards (
+	id integer primary key, nid integer not null, did integer not null, ord integer not null,
+	mod integer not null, usn integer not null, type integer not null, queue integer not null,
+	due integer not null, ivl integer not null, factor integer not null, reps integer not null,
+	lapses integer not null, left integer not null, odue integer not null, odid integer not null,
+	flags integer not null, data text not null
+);
+CREATE TABLE revlog (
+	id integer primary key, cid integer not null, usn integer not null, ease integer not null,
+	ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null,
+	type integer not null
+);
+CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+// ankiDeckConfig is Anki's default deck options group
+const ankiDeckConfig = `{"1": {"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60,
+"autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+"new": {"bury": true, "delays": [1, 10], "initialFactor": 2500, "ints": [1, 4, 7], "order": 1, "perDay": 20, "separate": true},
+"lapse": {"delays": [10], "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0},
+"rev": {"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 100}}}`
@@ -0,0 +1,11 @@
+// File: internal/export/anki_cgo.go
+
+//go:build cgo
+
+package export
+
+// CheckAnki reports whether Anki packages can be written. The SQLite driver
+// for the collection database is a cgo package, so this binary supports them.
+func CheckAnki() error {
+	return nil
+}
@@ -0,0 +1,14 @@
+// File: internal/export/anki_nocgo.go
+
+//go:build !cgo
+
+package export
+
+import "errors"
+
+// CheckAnki reports whether Anki packages can be written. The SQLite driver
+// for the collection database is a cgo package, which this binary was built
+// without.
+func CheckAnki() error {
+	return errors.New("anki export requires a build with cgo enabled (CGO_ENABLED=1 and a C compiler)")
+}
@@ -0,0 +1,132 @@
+// File: internal/export/dictd.go
+
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// dictdBase64 is the alphabet dictd uses for offsets and lengths in .index files
+const dictdBase64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// DictdWriter writes words as a dictd database: base.index and base.dict or
+// base.dict.dz. Inflected forms get their own index lines pointing to the
+// article of the headword.
+type DictdWriter struct {
+	*dictionaryFile
+}
+
+// NewDictdWriter creates a DictdWriter and writes the database header entries
+func NewDictdWriter(base string, options DictionaryOptions) (*DictdWriter, error) {
+	// dictd clients show plain text
+	if options.Format == "" {
+		options.Format = "text"
+	}
+	dict, err := newDictionaryFile(base, options)
+	if err != nil {
+		return nil, err
+	}
+
+	info := dict.options.Description
+	if info == "" {
+		info = "Entries crawled from Duden online by goden-crawler."
+	}
+	for _, header := range []struct{ headword, text string }{
+		{"00-database-allchars", ""},
+		{"00-database-utf8", ""},
+		{"00-database-short", dict.options.Name},
+		{"00-database-url", dudenSite},
+		{"00-database-info", fmt.Sprintf("%s\nCreated %s.", info, time.Now().Format("2006-01-02"))},
+	} {
+		if err := dict.add(header.headword, dictdArticle(header.headword, header.text), nil); err != nil {
+			return nil, err
+		}
+	}
+	return &DictdWriter{dictionaryFile: dict}, nil
+}
+
+// Write implements Writer
+func (d *DictdWriter) Write(word *models.Word) error {
+	article, err := d.render(word)
+	if err != nil {
+		return err
+	}
+	return d.add(word.Word, dictdArticle(word.Word, article), inflectedForms(word))
+}
+
+// Close implements Writer. It writes the index once all articles are known.
+func (d *DictdWriter) Close() error {
+	if err := d.finish(); err != nil {
+		return err
+	}
+
+	var lines []dictionaryEntry
+	for _, entry := range d.entries {
+		lines = append(lines, entry)
+		for _, form := range entry.forms {
+			lines = append(lines, dictionaryEntry{headword: form, offset: entry.offset, size: entry.size})
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return dictdCompare(lines[i].headword, lines[j].headword) < 0
+	})
+
+	index, err := os.Create(d.base + ".index")
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+
+	out := bufio.NewWriter(index)
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(out, "%s\t%s\t%s\n", line.headword, dictdNumber(line.offset), dictdNumber(line.size)); err != nil {
+			return err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return index.Close()
+}
+
+// dictdArticle formats an article as dictfmt does: the headword, then the indented text
+func dictdArticle(headword, text string) string {
+	var sb strings.Builder
+	sb.WriteString(headword + "\n")
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString("   " + line + "\n")
+	}
+	return sb.String()
+}
+
+// dictdNumber encodes a number in dictd's base64 notation
+func dictdNumber(n uint32) string {
+	if n == 0 {
+		return "A"
+	}
+	var digits []byte
+	for ; n > 0; n /= 64 {
+		digits = append([]byte{dictdBase64[n%64]}, digits...)
+	}
+	return string(digits)
+}
+
+// dictdCompare orders index lines case-insensitively, as dictd searches
+// databases with 00-database-allchars
+func dictdCompare(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
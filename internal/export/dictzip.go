@@ -0,0 +1,131 @@
+// File: internal/export/dictzip.go
+
+package export
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// dictzipChunkSize is the uncompressed size of a dictzip chunk, as used by dictzip(1)
+const dictzipChunkSize = 58315
+
+// dictzipFile compresses source into target in dictzip format: a gzip file whose
+// "RA" extra field lists independently compressed chunks, so that StarDict and
+// dictd readers can decompress single entries without reading the whole file.
+// Chunks are streamed to target; the chunk table is reserved in the header and
+// filled in once the chunk sizes are known.
+func dictzipFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	chunkCount := int((info.Size() + dictzipChunkSize - 1) / dictzipChunkSize)
+	extraLength := 10 + 2*chunkCount
+	if extraLength > 0xFFFF {
+		return fmt.Errorf("dictionary too large for dictzip (%d chunks)", chunkCount)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var header bytes.Buffer
+	header.Write([]byte{0x1f, 0x8b, 8, 0x04 | 0x08}) // magic, deflate, FEXTRA | FNAME
+	binary.Write(&header, binary.LittleEndian, uint32(0))
+	header.Write([]byte{2, 3}) // best compression, Unix
+	binary.Write(&header, binary.LittleEndian, uint16(extraLength))
+	header.WriteString("RA")
+	binary.Write(&header, binary.LittleEndian, uint16(extraLength-4))
+	binary.Write(&header, binary.LittleEndian, uint16(1)) // version
+	binary.Write(&header, binary.LittleEndian, uint16(dictzipChunkSize))
+	binary.Write(&header, binary.LittleEndian, uint16(chunkCount))
+	tableOffset := int64(header.Len())
+	header.Write(make([]byte, 2*chunkCount)) // chunk sizes, filled in below
+	header.WriteString(filepath.Base(source))
+	header.WriteByte(0)
+
+	if _, err := out.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	var (
+		chunk      bytes.Buffer // the current compressed chunk
+		chunkSizes = make([]uint16, 0, chunkCount)
+		checksum   = crc32.NewIEEE()
+		size       uint32
+		buf        = make([]byte, dictzipChunkSize)
+	)
+	compressor, err := flate.NewWriter(&chunk, flate.BestCompression)
+	if err != nil {
+		return err
+	}
+	for {
+		n, readErr := io.ReadFull(in, buf)
+		if n > 0 {
+			if len(chunkSizes) == chunkCount {
+				return fmt.Errorf("%s grew while it was compressed", source)
+			}
+			// Resetting the compressor clears the dictionary, making chunks independent
+			chunk.Reset()
+			compressor.Reset(&chunk)
+			if _, err := compressor.Write(buf[:n]); err != nil {
+				return err
+			}
+			if err := compressor.Flush(); err != nil {
+				return err
+			}
+			if chunk.Len() > 0xFFFF {
+				return fmt.Errorf("dictzip chunk does not fit in 64 KiB")
+			}
+			if _, err := out.Write(chunk.Bytes()); err != nil {
+				return err
+			}
+			chunkSizes = append(chunkSizes, uint16(chunk.Len()))
+			checksum.Write(buf[:n])
+			size += uint32(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+	if len(chunkSizes) != chunkCount {
+		return fmt.Errorf("%s shrank while it was compressed", source)
+	}
+
+	// An empty final block ends the deflate stream
+	if _, err := out.Write([]byte{0x03, 0x00}); err != nil {
+		return err
+	}
+	if err := binary.Write(out, binary.LittleEndian, checksum.Sum32()); err != nil {
+		return err
+	}
+	if err := binary.Write(out, binary.LittleEndian, size); err != nil {
+		return err
+	}
+
+	if _, err := out.Seek(tableOffset, io.SeekStart); err != nil {
+		return err
+	}
+	if err := binary.Write(out, binary.LittleEndian, chunkSizes); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,132 @@
+// File: internal/export/dump.go
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ReadDumpFile reads the words of a dump file, decompressing .gz and .zst files.
+// See ReadDump for the accepted layouts.
+func ReadDumpFile(path string, fn func(word *models.Word) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	switch CompressionFromPath(path) {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer decoder.Close()
+		r = decoder
+	}
+	return ReadDump(r, fn)
+}
+
+// ReadDump streams the words of a JSON or JSON Lines dump through fn. It
+// accepts a sequence of JSON values (one per line or not), each of which may be
+// a word, an array of words (as written by "export --format json"), or an
+// object of homonym entries keyed by slug (as written by "scrape --format json").
+// Records written with a newer schema version than this build knows are rejected.
+func ReadDump(r io.Reader, fn func(word *models.Word) error) error {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for record := 1; ; record++ {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+		if err := readDumpValue(value, fn); err != nil {
+			return fmt.Errorf("record %d: %w", record, err)
+		}
+	}
+}
+
+// readDumpValue decodes one top-level JSON value of a dump
+func readDumpValue(value json.RawMessage, fn func(word *models.Word) error) error {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return nil
+	}
+
+	if value[0] == '[' {
+		var values []json.RawMessage
+		if err := json.Unmarshal(value, &values); err != nil {
+			return err
+		}
+		for _, element := range values {
+			if err := readDumpValue(element, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(value, &fields); err != nil {
+		return err
+	}
+
+	// A word has a "word" field; anything else is an object of entries
+	if _, isWord := fields["word"]; !isWord {
+		for _, entry := range sortedKeys(fields) {
+			if err := readDumpValue(fields[entry], fn); err != nil {
+				return fmt.Errorf("entry %s: %w", entry, err)
+			}
+		}
+		return nil
+	}
+
+	if version, ok := fields["schema_version"]; ok {
+		var schemaVersion int
+		if err := json.Unmarshal(version, &schemaVersion); err != nil {
+			return fmt.Errorf("invalid schema_version: %w", err)
+		}
+		if schemaVersion > SchemaVersion {
+			return fmt.Errorf("schema version %d is newer than supported version %d", schemaVersion, SchemaVersion)
+		}
+	}
+
+	var word models.Word
+	if err := json.Unmarshal(value, &word); err != nil {
+		return err
+	}
+	if strings.TrimSpace(word.Word) == "" {
+		return fmt.Errorf("word record without a word")
+	}
+	return fn(&word)
+}
+
+// sortedKeys returns the keys of an object in order, so entries are read deterministically
+func sortedKeys(fields map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+// File: internal/export/jsonl.go
+
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression codecs of JSON Lines streams
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// CompressionFromPath derives the compression of a stream from its file name:
+// ".gz" selects gzip, ".zst" zstd, anything else none
+func CompressionFromPath(path string) string {
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		return CompressionGzip
+	case strings.HasSuffix(path, ".zst"), strings.HasSuffix(path, ".zstd"):
+		return CompressionZstd
+	}
+	return CompressionNone
+}
+
+// jsonlRecord is one line of a JSON Lines export: the complete word document
+// with the schema version added as a top-level field
+type jsonlRecord struct {
+	SchemaVersion int `json:"schema_version"`
+	*models.Word
+}
+
+// JSONLWriter writes words as JSON Lines, one complete word document per line
+type JSONLWriter struct {
+	buffer     *bufio.Writer
+	compressor io.WriteCloser // nil without compression
+	encoder    *json.Encoder
+}
+
+// NewJSONLWriter creates a JSONLWriter that compresses with the given codec
+func NewJSONLWriter(w io.Writer, compression string) (*JSONLWriter, error) {
+	j := &JSONLWriter{}
+
+	switch compression {
+	case "", CompressionNone:
+	case CompressionGzip:
+		j.compressor = gzip.NewWriter(w)
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		j.compressor = encoder
+	default:
+		return nil, fmt.Errorf("unsupported compression %q (use %s, %s or %s)", compression, CompressionNone, CompressionGzip, CompressionZstd)
+	}
+
+	if j.compressor != nil {
+		w = j.compressor
+	}
+	j.buffer = bufio.NewWriter(w)
+	j.encoder = json.NewEncoder(j.buffer)
+	j.encoder.SetEscapeHTML(false)
+	return j, nil
+}
+
+// Write implements Writer
+func (j *JSONLWriter) Write(word *models.Word) error {
+	return j.encoder.Encode(jsonlRecord{SchemaVersion: SchemaVersion, Word: word})
+}
+
+// Close implements Writer
+func (j *JSONLWriter) Close() error {
+	if err := j.buffer.Flush(); err != nil {
+		return err
+	}
+	if j.compressor != nil {
+		return j.compressor.Close()
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// File: internal/export/lexicon.go
+
+package export
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// Writer writes words to an export format one at a time. Export formats share
+// the interface with the output formats of the formatter package.
+type Writer = formatter.WordWriter
+
+// multiWriter writes every word to several writers
+type multiWriter []Writer
+
+// MultiWriter returns a Writer that writes to all given writers, like io.MultiWriter
+func MultiWriter(writers ...Writer) Writer {
+	return multiWriter(writers)
+}
+
+// Write implements Writer
+func (m multiWriter) Write(word *models.Word) error {
+	for _, writer := range m {
+		if err := writer.Write(word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Writer. All writers are closed; the first error is returned.
+func (m multiWriter) Close() error {
+	var first error
+	for _, writer := range m {
+		if err := writer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// partOfSpeech is a Duden word class mapped to Universal Dependencies and LexInfo
+type partOfSpeech struct {
+	ud      string // Universal Dependencies tag used as TEI @norm
+	lexinfo string // LexInfo individual
+}
+
+// partsOfSpeech maps the Duden "Wortart" to standard part-of-speech values
+var partsOfSpeech = map[string]partOfSpeech{
+	"substantiv":   {ud: "NOUN", lexinfo: "noun"},
+	"eigenname":    {ud: "PROPN", lexinfo: "properNoun"},
+	"verb":         {ud: "VERB", lexinfo: "verb"},
+	"adjektiv":     {ud: "ADJ", lexinfo: "adjective"},
+	"adverb":       {ud: "ADV", lexinfo: "adverb"},
+	"präposition":  {ud: "ADP", lexinfo: "preposition"},
+	"konjunktion":  {ud: "CCONJ", lexinfo: "conjunction"},
+	"pronomen":     {ud: "PRON", lexinfo: "pronoun"},
+	"artikel":      {ud: "DET", lexinfo: "article"},
+	"interjektion": {ud: "INTJ", lexinfo: "interjection"},
+	"partikel":     {ud: "PART", lexinfo: "particle"},
+	"numerale":     {ud: "NUM", lexinfo: "numeral"},
+	"zahlwort":     {ud: "NUM", lexinfo: "numeral"},
+	"abkürzung":    {ud: "X", lexinfo: "abbreviation"},
+}
+
+// genders maps Duden gender labels and articles to LexInfo genders
+var genders = map[string]string{
+	"maskulinum": "masculine", "maskulin": "masculine", "der": "masculine",
+	"femininum": "feminine", "feminin": "feminine", "die": "feminine",
+	"neutrum": "neuter", "das": "neuter",
+}
+
+// wordPOS returns the part of speech of a word, if its word class is known
+func wordPOS(word *models.Word) (label string, pos partOfSpeech, ok bool) {
+	for _, wordType := range word.WordType {
+		for _, part := range strings.Fields(strings.ToLower(wordType)) {
+			if pos, ok := partsOfSpeech[strings.Trim(part, ",.;")]; ok {
+				return wordType, pos, true
+			}
+		}
+	}
+	return "", partOfSpeech{}, false
+}
+
+// wordGender returns the grammatical gender of a noun from its word type or article
+func wordGender(word *models.Word) string {
+	for _, wordType := range word.WordType {
+		if gender, ok := genders[strings.ToLower(strings.TrimSpace(wordType))]; ok {
+			return gender
+		}
+	}
+	// "die" is also the plural article, so only trust articles of nouns
+	if _, pos, ok := wordPOS(word); ok && pos.ud == "NOUN" {
+		return genders[strings.ToLower(word.Article)]
+	}
+	return ""
+}
+
+// entryID returns an identifier for a word that is a valid XML NCName and IRI segment
+func entryID(word *models.Word) string {
+	id := word.ID
+	if id == "" {
+		id = word.Word
+	}
+
+	var sb strings.Builder
+	for i, r := range id {
+		switch {
+		case unicode.IsLetter(r), r == '_':
+			sb.WriteRune(r)
+		case unicode.IsDigit(r), r == '-', r == '.':
+			if i == 0 {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// senseNumber returns the displayed number of a meaning, falling back to its position
+func senseNumber(meaning models.Meaning, fallback string) string {
+	if meaning.Number != "" {
+		return meaning.Number
+	}
+	return fallback
+}
+
+// subSenseNumber returns the full number of a sub-meaning as Duden shows it:
+// 1a, 1b, … Duden stores only the letter, so the parent number is prefixed.
+func subSenseNumber(parent string, index int, meaning models.Meaning) string {
+	number := meaning.Number
+	if number == "" {
+		number = string(rune('a' + index))
+	}
+	if strings.HasPrefix(number, parent) {
+		return number
+	}
+	return parent + number
+}
+
+// collapseSpace joins the whitespace-separated parts of scraped text with single spaces
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,355 @@
+// File: internal/export/ontolex.go
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// RDF syntaxes supported by OntoLexWriter
+const (
+	SyntaxTurtle = "turtle"
+	SyntaxJSONLD = "jsonld"
+)
+
+// DefaultOntoLexBase is the IRI prefix of exported lexicon resources
+const DefaultOntoLexBase = "urn:goden-crawler:"
+
+// ontolexPrefixes are the vocabularies used by the export, in output order
+var ontolexPrefixes = []struct{ prefix, iri string }{
+	{"ontolex", "http://www.w3.org/ns/lemon/ontolex#"},
+	{"lime", "http://www.w3.org/ns/lemon/lime#"},
+	{"lexinfo", "http://www.lexinfo.net/ontology/3.0/lexinfo#"},
+	{"skos", "http://www.w3.org/2004/02/skos/core#"},
+	{"dct", "http://purl.org/dc/terms/"},
+	{"rdfs", "http://www.w3.org/2000/01/rdf-schema#"},
+}
+
+// rdfTerm is the object of a statement: a full IRI, a prefixed name such as
+// "lexinfo:noun", or a literal with an optional language tag
+type rdfTerm struct {
+	IRI   string
+	Name  string
+	Value string
+	Lang  string
+}
+
+// rdfProperty is a predicate (as a prefixed name) with its object
+type rdfProperty struct {
+	Predicate string
+	Object    rdfTerm
+}
+
+// rdfNode is a resource with its types and properties
+type rdfNode struct {
+	ID         string
+	Types      []string
+	Properties []rdfProperty
+}
+
+// add appends a property, ignoring empty literals
+func (n *rdfNode) add(predicate string, object rdfTerm) {
+	if object.IRI == "" && object.Name == "" && object.Value == "" {
+		return
+	}
+	n.Properties = append(n.Properties, rdfProperty{Predicate: predicate, Object: object})
+}
+
+// iri returns an IRI term
+func iri(value string) rdfTerm { return rdfTerm{IRI: value} }
+
+// name returns a prefixed-name term
+func name(value string) rdfTerm { return rdfTerm{Name: value} }
+
+// german returns a German literal
+func german(value string) rdfTerm { return rdfTerm{Value: value, Lang: "de"} }
+
+// OntoLexWriter writes words as an OntoLex-Lemon lexicon in Turtle or JSON-LD
+type OntoLexWriter struct {
+	out     io.Writer
+	syntax  string
+	base    string
+	lexicon string
+	written int // JSON-LD nodes written, to place separators
+}
+
+// NewOntoLexWriter creates an OntoLexWriter and writes the lexicon description.
+// Resources are named by appending entry IDs to base.
+func NewOntoLexWriter(w io.Writer, syntax, base, title string) (*OntoLexWriter, error) {
+	if syntax != SyntaxTurtle && syntax != SyntaxJSONLD {
+		return nil, fmt.Errorf("unsupported RDF syntax %q (use %s or %s)", syntax, SyntaxTurtle, SyntaxJSONLD)
+	}
+	if base == "" {
+		base = DefaultOntoLexBase
+	}
+	if title == "" {
+		title = "Goden Crawler Dictionary"
+	}
+
+	o := &OntoLexWriter{out: w, syntax: syntax, base: base, lexicon: base + "lexicon"}
+	if err := o.writeHeader(); err != nil {
+		return nil, err
+	}
+
+	lexicon := rdfNode{ID: o.lexicon, Types: []string{"lime:Lexicon"}}
+	lexicon.add("lime:language", rdfTerm{Value: "de"})
+	lexicon.add("dct:title", rdfTerm{Value: title})
+	lexicon.add("dct:source", iri(dudenSite))
+	if err := o.writeNode(lexicon); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Write implements Writer
+func (o *OntoLexWriter) Write(word *models.Word) error {
+	for _, node := range o.nodes(word) {
+		if err := o.writeNode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Writer
+func (o *OntoLexWriter) Close() error {
+	if o.syntax == SyntaxJSONLD {
+		_, err := io.WriteString(o.out, "\n  ]\n}\n")
+		return err
+	}
+	return nil
+}
+
+// nodes maps a word to its lexical entry, forms and senses
+func (o *OntoLexWriter) nodes(word *models.Word) []rdfNode {
+	id := entryID(word)
+	entryIRI := o.base + id
+
+	// Entries are linked from the lexicon as they are written
+	membership := rdfNode{ID: o.lexicon}
+	membership.add("lime:entry", iri(entryIRI))
+
+	entry := rdfNode{ID: entryIRI, Types: []string{"ontolex:LexicalEntry"}}
+	if strings.Contains(strings.TrimSpace(word.Word), " ") {
+		entry.Types = append(entry.Types, "ontolex:MultiwordExpression")
+	} else {
+		entry.Types = append(entry.Types, "ontolex:Word")
+	}
+	entry.add("rdfs:label", german(word.Word))
+
+	lemma := rdfNode{ID: entryIRI + "_lemma", Types: []string{"ontolex:Form"}}
+	lemma.add("ontolex:writtenRep", german(word.Word))
+	for _, pronunciation := range word.Pronunciation {
+		lemma.add("ontolex:phoneticRep", rdfTerm{Value: pronunciation.Phonetic, Lang: "de-fonipa"})
+	}
+	entry.add("ontolex:canonicalForm", iri(lemma.ID))
+	nodes := []rdfNode{membership, entry, lemma}
+
+	for i, form := range word.InflectedForms {
+		if form == word.Word {
+			continue
+		}
+		other := rdfNode{ID: fmt.Sprintf("%s_form%d", entryIRI, i+1), Types: []string{"ontolex:Form"}}
+		other.add("ontolex:writtenRep", german(form))
+		nodes[1].add("ontolex:otherForm", iri(other.ID))
+		nodes = append(nodes, other)
+	}
+
+	if _, pos, ok := wordPOS(word); ok {
+		nodes[1].add("lexinfo:partOfSpeech", name("lexinfo:"+pos.lexinfo))
+	}
+	if gender := wordGender(word); gender != "" {
+		nodes[1].add("lexinfo:gender", name("lexinfo:"+gender))
+	}
+	if word.Etymology != nil {
+		nodes[1].add("skos:historyNote", german(collapseSpace(word.Etymology.Text)))
+	}
+
+	// Sub-meanings are flattened into senses of the entry and keep their Duden number
+	var addSenses func(meanings []models.Meaning, parent string)
+	addSenses = func(meanings []models.Meaning, parent string) {
+		for i, meaning := range meanings {
+			number := senseNumber(meaning, fmt.Sprint(i+1))
+			if parent != "" {
+				number = subSenseNumber(parent, i, meaning)
+			}
+
+			sense := rdfNode{ID: entryIRI + "_" + entryID(&models.Word{ID: "sense" + number}), Types: []string{"ontolex:LexicalSense"}}
+			sense.add("skos:notation", rdfTerm{Value: number})
+			sense.add("skos:definition", german(collapseSpace(meaning.Text)))
+			for _, label := range append(append([]string{}, meaning.Register...), meaning.Domain...) {
+				sense.add("skos:scopeNote", german(label))
+			}
+			for _, example := range meaning.Examples {
+				sense.add("skos:example", german(collapseSpace(example.Text)))
+			}
+			nodes[1].add("ontolex:sense", iri(sense.ID))
+			nodes = append(nodes, sense)
+
+			addSenses(meaning.SubMeanings, number)
+		}
+	}
+	addSenses(word.Meanings, "")
+
+	for _, synonym := range word.Synonyms {
+		if target := o.linkIRI(synonym.Link); target != "" {
+			nodes[1].add("lexinfo:synonym", iri(target))
+		}
+	}
+	for _, antonym := range word.Antonyms {
+		if target := o.linkIRI(antonym.Link); target != "" {
+			nodes[1].add("lexinfo:antonym", iri(target))
+		}
+	}
+	if word.SourceURL != "" {
+		nodes[1].add("dct:source", iri(word.SourceURL))
+	}
+	return nodes
+}
+
+// linkIRI names the entry a Duden link such as "/rechtschreibung/Haus" points to
+func (o *OntoLexWriter) linkIRI(link string) string {
+	if !strings.Contains(link, "/rechtschreibung/") {
+		return ""
+	}
+	return o.base + entryID(&models.Word{ID: path.Base(link)})
+}
+
+// writeHeader writes the prefixes or the JSON-LD context
+func (o *OntoLexWriter) writeHeader() error {
+	if o.syntax == SyntaxTurtle {
+		var sb strings.Builder
+		for _, prefix := range ontolexPrefixes {
+			sb.WriteString(fmt.Sprintf("@prefix %s: <%s> .\n", prefix.prefix, prefix.iri))
+		}
+		_, err := io.WriteString(o.out, sb.String())
+		return err
+	}
+
+	context := make(map[string]string, len(ontolexPrefixes))
+	for _, prefix := range ontolexPrefixes {
+		context[prefix.prefix] = prefix.iri
+	}
+	data, err := json.MarshalIndent(context, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(o.out, "{\n  \"@context\": %s,\n  \"@graph\": [", data)
+	return err
+}
+
+// writeNode writes a node in the configured syntax
+func (o *OntoLexWriter) writeNode(node rdfNode) error {
+	if o.syntax == SyntaxJSONLD {
+		return o.writeJSONLDNode(node)
+	}
+	return o.writeTurtleNode(node)
+}
+
+// writeTurtleNode writes a node as one Turtle statement block
+func (o *OntoLexWriter) writeTurtleNode(node rdfNode) error {
+	var sb strings.Builder
+	sb.WriteString("\n" + turtleIRI(node.ID))
+
+	separator := " "
+	if len(node.Types) > 0 {
+		sb.WriteString(" a " + strings.Join(node.Types, ", "))
+		separator = " ;\n    "
+	}
+	for i, property := range node.Properties {
+		if i > 0 && property.Predicate == node.Properties[i-1].Predicate {
+			sb.WriteString(", " + turtleTerm(property.Object))
+			continue
+		}
+		sb.WriteString(separator + property.Predicate + " " + turtleTerm(property.Object))
+		separator = " ;\n    "
+	}
+	sb.WriteString(" .\n")
+
+	_, err := io.WriteString(o.out, sb.String())
+	return err
+}
+
+// writeJSONLDNode writes a node as an element of the JSON-LD @graph
+func (o *OntoLexWriter) writeJSONLDNode(node rdfNode) error {
+	object := map[string]interface{}{"@id": node.ID}
+	if len(node.Types) > 0 {
+		object["@type"] = node.Types
+	}
+	for _, property := range node.Properties {
+		value := jsonLDTerm(property.Object)
+		switch existing := object[property.Predicate].(type) {
+		case nil:
+			object[property.Predicate] = value
+		case []interface{}:
+			object[property.Predicate] = append(existing, value)
+		default:
+			object[property.Predicate] = []interface{}{existing, value}
+		}
+	}
+
+	data, err := json.MarshalIndent(object, "    ", "  ")
+	if err != nil {
+		return err
+	}
+	separator := "\n    "
+	if o.written > 0 {
+		separator = ",\n    "
+	}
+	o.written++
+	_, err = fmt.Fprintf(o.out, "%s%s", separator, data)
+	return err
+}
+
+// turtleTerm serializes a term in Turtle
+func turtleTerm(term rdfTerm) string {
+	switch {
+	case term.IRI != "":
+		return turtleIRI(term.IRI)
+	case term.Name != "":
+		return term.Name
+	}
+
+	literal := `"` + turtleEscaper.Replace(term.Value) + `"`
+	if term.Lang != "" {
+		literal += "@" + term.Lang
+	}
+	return literal
+}
+
+// turtleEscaper escapes string literals
+var turtleEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// turtleIRI writes an IRI reference, percent-encoding characters Turtle does not allow in IRIs
+func turtleIRI(value string) string {
+	var sb strings.Builder
+	sb.WriteString("<")
+	for _, r := range value {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			sb.WriteString(fmt.Sprintf("%%%02X", r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// jsonLDTerm serializes a term as a JSON-LD value
+func jsonLDTerm(term rdfTerm) interface{} {
+	switch {
+	case term.IRI != "":
+		return map[string]string{"@id": term.IRI}
+	case term.Name != "":
+		return map[string]string{"@id": term.Name}
+	case term.Lang != "":
+		return map[string]string{"@value": term.Value, "@language": term.Lang}
+	}
+	return term.Value
+}
@@ -0,0 +1,65 @@
+// File: internal/export/parquet.go
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetCodecs maps codec names to Parquet compression codecs
+var parquetCodecs = map[string]parquet.CompressionCodec{
+	"none":   parquet.CompressionCodec_UNCOMPRESSED,
+	"snappy": parquet.CompressionCodec_SNAPPY,
+	"gzip":   parquet.CompressionCodec_GZIP,
+	"zstd":   parquet.CompressionCodec_ZSTD,
+}
+
+// parquetRowGroupSize keeps row groups small enough to be written without much memory
+const parquetRowGroupSize = 32 * 1024 * 1024
+
+// ParquetWriter writes words as flattened WordRecord rows to a Parquet file.
+// The schema version is also stored in the file metadata as
+// "goden_crawler.schema_version".
+type ParquetWriter struct {
+	writer *writer.ParquetWriter
+}
+
+// NewParquetWriter creates a ParquetWriter with the given codec (none, snappy, gzip or zstd)
+func NewParquetWriter(w io.Writer, codec string) (*ParquetWriter, error) {
+	if codec == "" {
+		codec = "snappy"
+	}
+	compression, ok := parquetCodecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("unsupported Parquet compression %q (use none, snappy, gzip or zstd)", codec)
+	}
+
+	pw, err := writer.NewParquetWriterFromWriter(w, new(WordRecord), 1)
+	if err != nil {
+		return nil, err
+	}
+	pw.CompressionType = compression
+	pw.RowGroupSize = parquetRowGroupSize
+
+	version := strconv.Itoa(SchemaVersion)
+	pw.Footer.KeyValueMetadata = []*parquet.KeyValue{
+		{Key: "goden_crawler.schema_version", Value: &version},
+	}
+	return &ParquetWriter{writer: pw}, nil
+}
+
+// Write implements Writer
+func (p *ParquetWriter) Write(word *models.Word) error {
+	return p.writer.Write(NewWordRecord(word))
+}
+
+// Close implements Writer. It writes the Parquet footer.
+func (p *ParquetWriter) Close() error {
+	return p.writer.WriteStop()
+}
@@ -0,0 +1,149 @@
+// File: internal/export/records.go
+
+package export
+
+import (
+	"fmt"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// SchemaVersion is the version of the JSON Lines and Parquet record layouts.
+// Increase it whenever a field is renamed, removed or changes meaning; adding
+// fields does not require a new version. The layouts are documented in the
+// README under "Export Schema".
+const SchemaVersion = 1
+
+// WordRecord is the flattened layout of a word used for Parquet. Nested
+// values are reduced to lists of strings; meanings are numbered like Duden
+// ("1", "1a", …) so that the tree can be read back from meaning_numbers.
+type WordRecord struct {
+	SchemaVersion   int32    `parquet:"name=schema_version, type=INT32"`
+	ID              string   `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Word            string   `parquet:"name=word, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LookupKey       string   `parquet:"name=lookup_key, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Article         string   `parquet:"name=article, type=BYTE_ARRAY, convertedtype=UTF8"`
+	WordType        []string `parquet:"name=word_type, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Frequency       string   `parquet:"name=frequency, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Grammar         string   `parquet:"name=grammar, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Plural          string   `parquet:"name=plural, type=BYTE_ARRAY, convertedtype=UTF8"`
+	InflectedForms  []string `parquet:"name=inflected_forms, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	MeaningNumbers  []string `parquet:"name=meaning_numbers, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Meanings        []string `parquet:"name=meanings, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Examples        []string `parquet:"name=examples, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Idioms          []string `parquet:"name=idioms, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Synonyms        []string `parquet:"name=synonyms, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Antonyms        []string `parquet:"name=antonyms, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Collocations    []string `parquet:"name=collocations, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Compounds       []string `parquet:"name=compounds, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	WordFamily      []string `parquet:"name=word_family, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Phonetic        string   `parquet:"name=phonetic, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AudioURL        string   `parquet:"name=audio_url, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Syllables       string   `parquet:"name=syllables, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Etymology       string   `parquet:"name=etymology, type=BYTE_ARRAY, convertedtype=UTF8"`
+	EtymologySource []string `parquet:"name=etymology_sources, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	SourceURL       string   `parquet:"name=source_url, type=BYTE_ARRAY, convertedtype=UTF8"`
+	FetchedAt       int64    `parquet:"name=fetched_at, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+}
+
+// NewWordRecord flattens a word
+func NewWordRecord(word *models.Word) WordRecord {
+	record := WordRecord{
+		SchemaVersion:  SchemaVersion,
+		ID:             word.ID,
+		Word:           word.Word,
+		LookupKey:      word.LookupKey,
+		Article:        word.Article,
+		WordType:       nonNil(word.WordType),
+		Frequency:      word.Frequency,
+		Grammar:        word.Grammar,
+		Plural:         word.Plural(),
+		InflectedForms: nonNil(word.InflectedForms),
+		SourceURL:      word.SourceURL,
+	}
+	if record.LookupKey == "" {
+		record.LookupKey = models.LookupKey(word.Word)
+	}
+	if !word.FetchedAt.IsZero() {
+		record.FetchedAt = word.FetchedAt.UnixMilli()
+	}
+
+	var addMeanings func(meanings []models.Meaning, parent string)
+	addMeanings = func(meanings []models.Meaning, parent string) {
+		for i, meaning := range meanings {
+			number := senseNumber(meaning, fmt.Sprint(i+1))
+			if parent != "" {
+				number = subSenseNumber(parent, i, meaning)
+			}
+
+			record.MeaningNumbers = append(record.MeaningNumbers, number)
+			record.Meanings = append(record.Meanings, collapseSpace(meaning.Text))
+			for _, example := range meaning.Examples {
+				record.Examples = append(record.Examples, collapseSpace(example.Text))
+			}
+			for _, idiom := range meaning.Idioms {
+				text := idiom.Text
+				if idiom.Explanation != "" {
+					text += " – " + idiom.Explanation
+				}
+				record.Idioms = append(record.Idioms, collapseSpace(text))
+			}
+			addMeanings(meaning.SubMeanings, number)
+		}
+	}
+	addMeanings(word.Meanings, "")
+
+	for _, synonym := range word.Synonyms {
+		record.Synonyms = append(record.Synonyms, synonym.Text)
+	}
+	record.Antonyms = relatedTexts(word.Antonyms)
+	for _, collocation := range word.Collocations {
+		record.Collocations = append(record.Collocations, collocation.Text)
+	}
+	record.Compounds = relatedTexts(word.Compounds)
+	record.WordFamily = relatedTexts(word.WordFamily)
+
+	for _, pronunciation := range word.Pronunciation {
+		if record.Phonetic == "" {
+			record.Phonetic = pronunciation.Phonetic
+		}
+		if record.AudioURL == "" {
+			record.AudioURL = pronunciation.Audio
+		}
+	}
+	if word.Spelling != nil {
+		record.Syllables = word.Spelling.SyllabicDivision
+	}
+	if word.Etymology != nil {
+		record.Etymology = collapseSpace(word.Etymology.Text)
+		for _, source := range word.Etymology.Sources {
+			record.EtymologySource = append(record.EtymologySource, source.Word)
+		}
+	}
+
+	record.MeaningNumbers = nonNil(record.MeaningNumbers)
+	record.Meanings = nonNil(record.Meanings)
+	record.Examples = nonNil(record.Examples)
+	record.Idioms = nonNil(record.Idioms)
+	record.Synonyms = nonNil(record.Synonyms)
+	record.Collocations = nonNil(record.Collocations)
+	record.EtymologySource = nonNil(record.EtymologySource)
+	return record
+}
+
+// relatedTexts returns the texts of related words
+func relatedTexts(words []models.RelatedWord) []string {
+	texts := make([]string, 0, len(words))
+	for _, word := range words {
+		texts = append(texts, word.Text)
+	}
+	return texts
+}
+
+// nonNil returns an empty list for nil, so that lists are written as empty rather than missing
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,317 @@
+// File: internal/export/stardict.go
+
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// DictionaryOptions configures StarDict and dictd exports
+type DictionaryOptions struct {
+	Name        string // book name shown by dictionary apps
+	Description string
+	Format      string // output format of the entries, e.g. "html" or "text"
+	Compress    bool   // write .dict.dz (dictzip) instead of .dict
+}
+
+// dictionaryEntry is a headword with the position of its article in the .dict file
+type dictionaryEntry struct {
+	headword string
+	offset   uint32
+	size     uint32
+	forms    []string // inflected forms indexed as synonyms
+}
+
+// dictionaryFile writes entry articles to a .dict file and remembers their positions
+type dictionaryFile struct {
+	options DictionaryOptions
+	base    string
+	file    *os.File
+	out     *bufio.Writer
+	offset  uint64
+	entries []dictionaryEntry
+}
+
+// newDictionaryFile creates base.dict for writing
+func newDictionaryFile(base string, options DictionaryOptions) (*dictionaryFile, error) {
+	if options.Name == "" {
+		options.Name = "Goden Crawler"
+	}
+	if options.Format == "" {
+		options.Format = "html"
+	}
+	if _, exists := formatter.Default().Get(options.Format); !exists {
+		return nil, fmt.Errorf("unknown entry format: %s", options.Format)
+	}
+
+	file, err := os.Create(base + ".dict")
+	if err != nil {
+		return nil, err
+	}
+	return &dictionaryFile{options: options, base: base, file: file, out: bufio.NewWriter(file)}, nil
+}
+
+// add appends an article and records it under headword
+func (d *dictionaryFile) add(headword, article string, forms []string) error {
+	if d.offset+uint64(len(article)) > 0xFFFFFFFF {
+		return fmt.Errorf("dictionary exceeds 4 GiB")
+	}
+	if _, err := d.out.WriteString(article); err != nil {
+		return err
+	}
+	d.entries = append(d.entries, dictionaryEntry{
+		headword: headword,
+		offset:   uint32(d.offset),
+		size:     uint32(len(article)),
+		forms:    forms,
+	})
+	d.offset += uint64(len(article))
+	return nil
+}
+
+// render formats a word as a dictionary article. HTML pages are reduced to
+// their body, since dictionary apps embed articles in their own page.
+func (d *dictionaryFile) render(word *models.Word) (string, error) {
+	article, err := formatter.FormatOutput(word, d.options.Format)
+	if err != nil {
+		return "", err
+	}
+	if d.isHTML() {
+		if start := strings.Index(article, "<body>"); start >= 0 {
+			article = article[start+len("<body>"):]
+		}
+		if end := strings.LastIndex(article, "</body>"); end >= 0 {
+			article = article[:end]
+		}
+	}
+	return strings.TrimSpace(article), nil
+}
+
+// isHTML reports whether entries are rendered as HTML
+func (d *dictionaryFile) isHTML() bool {
+	registration, _ := formatter.Default().Get(d.options.Format)
+	return strings.HasPrefix(registration.MIMEType, "text/html")
+}
+
+// finish flushes the .dict file and compresses it if requested
+func (d *dictionaryFile) finish() error {
+	if err := d.out.Flush(); err != nil {
+		return err
+	}
+	if err := d.file.Close(); err != nil {
+		return err
+	}
+	if !d.options.Compress {
+		return nil
+	}
+	if err := dictzipFile(d.base+".dict", d.base+".dict.dz"); err != nil {
+		return err
+	}
+	return os.Remove(d.base + ".dict")
+}
+
+// inflectedForms returns the distinct inflected forms of a word other than the headword
+func inflectedForms(word *models.Word) []string {
+	seen := map[string]bool{word.Word: true}
+	var forms []string
+	for _, form := range append(append([]string{}, word.InflectedForms...), pluralForms(word.Plural())...) {
+		form = strings.TrimSpace(form)
+		if form != "" && !seen[form] {
+			seen[form] = true
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// optionalPart matches optional letters in Duden forms, e.g. "[e]s" in "Brot[e]s"
+var optionalPart = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// alternativeForms separates alternative plurals such as "Wasser und Wässer"
+var alternativeForms = regexp.MustCompile(`\s+(?:und|oder)\s+|,|/`)
+
+// pluralForms turns the plural from the grammar text, such as "die Wasser und
+// Wässer" or "die TEE[s]", into bare forms: Wasser, Wässer, TEE, TEEs
+func pluralForms(plural string) []string {
+	var forms []string
+	for _, part := range alternativeForms.Split(plural, -1) {
+		part = strings.TrimSpace(part)
+		for _, article := range []string{"die ", "der ", "den ", "des "} {
+			part = strings.TrimPrefix(part, article)
+		}
+		if part == "" {
+			continue
+		}
+		forms = append(forms, optionalPart.ReplaceAllString(part, ""))
+		if optionalPart.MatchString(part) {
+			forms = append(forms, optionalPart.ReplaceAllString(part, "$1"))
+		}
+	}
+	return forms
+}
+
+// StarDictWriter writes words as a StarDict dictionary: base.ifo, base.idx,
+// base.syn and base.dict or base.dict.dz. Inflected forms are written to the
+// .syn file, so looking up "Häuser" finds "Haus".
+type StarDictWriter struct {
+	*dictionaryFile
+}
+
+// NewStarDictWriter creates a StarDictWriter for files named base.ifo, base.idx, …
+func NewStarDictWriter(base string, options DictionaryOptions) (*StarDictWriter, error) {
+	dict, err := newDictionaryFile(base, options)
+	if err != nil {
+		return nil, err
+	}
+	return &StarDictWriter{dictionaryFile: dict}, nil
+}
+
+// Write implements Writer
+func (s *StarDictWriter) Write(word *models.Word) error {
+	article, err := s.render(word)
+	if err != nil {
+		return err
+	}
+	return s.add(word.Word, article, inflectedForms(word))
+}
+
+// Close implements Writer. It writes the index files once all articles are known.
+func (s *StarDictWriter) Close() error {
+	if err := s.finish(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(s.entries, func(i, j int) bool {
+		return stardictCompare(s.entries[i].headword, s.entries[j].headword) < 0
+	})
+
+	idx, err := os.Create(s.base + ".idx")
+	if err != nil {
+		return err
+	}
+	defer idx.Close()
+
+	type synonym struct {
+		form  string
+		index uint32
+	}
+	var synonyms []synonym
+
+	out := bufio.NewWriter(idx)
+	var idxSize int
+	for i, entry := range s.entries {
+		record := make([]byte, 0, len(entry.headword)+9)
+		record = append(record, entry.headword...)
+		record = append(record, 0)
+		record = binary.BigEndian.AppendUint32(record, entry.offset)
+		record = binary.BigEndian.AppendUint32(record, entry.size)
+		if _, err := out.Write(record); err != nil {
+			return err
+		}
+		idxSize += len(record)
+
+		for _, form := range entry.forms {
+			synonyms = append(synonyms, synonym{form: form, index: uint32(i)})
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	if len(synonyms) > 0 {
+		sort.SliceStable(synonyms, func(i, j int) bool {
+			return stardictCompare(synonyms[i].form, synonyms[j].form) < 0
+		})
+
+		syn, err := os.Create(s.base + ".syn")
+		if err != nil {
+			return err
+		}
+		defer syn.Close()
+
+		out := bufio.NewWriter(syn)
+		for _, synonym := range synonyms {
+			record := append([]byte(synonym.form), 0)
+			record = binary.BigEndian.AppendUint32(record, synonym.index)
+			if _, err := out.Write(record); err != nil {
+				return err
+			}
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+	}
+
+	typeSequence := "m"
+	if s.isHTML() {
+		typeSequence = "h"
+	}
+
+	var ifo strings.Builder
+	ifo.WriteString("StarDict's dict ifo file\n")
+	ifo.WriteString("version=3.0.0\n")
+	ifo.WriteString(fmt.Sprintf("bookname=%s\n", ifoValue(s.options.Name)))
+	ifo.WriteString(fmt.Sprintf("wordcount=%d\n", len(s.entries)))
+	if len(synonyms) > 0 {
+		ifo.WriteString(fmt.Sprintf("synwordcount=%d\n", len(synonyms)))
+	}
+	ifo.WriteString(fmt.Sprintf("idxfilesize=%d\n", idxSize))
+	ifo.WriteString("author=goden-crawler\n")
+	ifo.WriteString(fmt.Sprintf("website=%s\n", dudenSite))
+	if s.options.Description != "" {
+		ifo.WriteString(fmt.Sprintf("description=%s\n", ifoValue(s.options.Description)))
+	}
+	ifo.WriteString(fmt.Sprintf("date=%s\n", time.Now().Format("2006.01.02")))
+	ifo.WriteString(fmt.Sprintf("sametypesequence=%s\n", typeSequence))
+
+	return os.WriteFile(s.base+".ifo", []byte(ifo.String()), 0644)
+}
+
+// ifoValue keeps a value on one line, as .ifo files are line-based
+func ifoValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// stardictCompare orders index entries as StarDict does: ASCII case-insensitively,
+// then byte-wise to break ties
+func stardictCompare(a, b string) int {
+	if c := asciiCaseCompare(a, b); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// asciiCaseCompare compares strings like g_ascii_strcasecmp, folding only ASCII letters
+func asciiCaseCompare(a, b string) int {
+	lower := func(c byte) byte {
+		if 'A' <= c && c <= 'Z' {
+			return c + 'a' - 'A'
+		}
+		return c
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if ca, cb := lower(a[i]), lower(b[i]); ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,315 @@
+// File: internal/export/tei.go
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// teiHeader opens a TEI Lex-0 document. Title, date and source are filled in by NewTEIWriter.
+const teiHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<TEI xmlns="http://www.tei-c.org/ns/1.0" xml:lang="de">
+  <teiHeader>
+    <fileDesc>
+      <titleStmt>
+        <title>%s</title>
+      </titleStmt>
+      <publicationStmt>
+        <p>Exported by goden-crawler on <date when="%s">%s</date></p>
+      </publicationStmt>
+      <sourceDesc>
+        <p>Entries crawled from Duden online, <ref target="https://www.duden.de">https://www.duden.de</ref></p>
+      </sourceDesc>
+    </fileDesc>
+  </teiHeader>
+  <text>
+    <body>
+`
+
+// teiFooter closes a TEI Lex-0 document
+const teiFooter = `
+    </body>
+  </text>
+</TEI>
+`
+
+// TEI Lex-0 elements. Entries and senses carry xml:id; grammatical values are
+// normalized in @norm (Universal Dependencies part of speech, LexInfo gender).
+type (
+	teiEntry struct {
+		XMLName xml.Name    `xml:"entry"`
+		ID      string      `xml:"http://www.w3.org/XML/1998/namespace id,attr"`
+		Lang    string      `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+		Type    string      `xml:"type,attr,omitempty"`
+		Forms   []teiForm   `xml:"form"`
+		GramGrp *teiGramGrp `xml:"gramGrp"`
+		Usages  []teiUsage  `xml:"usg"`
+		Senses  []teiSense  `xml:"sense"`
+		Etym    *teiEtym    `xml:"etym"`
+		Xrs     []teiXr     `xml:"xr"`
+		Related []teiEntry  `xml:"entry"`
+		Source  *teiRef     `xml:"note>ref"`
+	}
+
+	teiForm struct {
+		Type  string    `xml:"type,attr"`
+		Orth  string    `xml:"orth"`
+		Hyph  string    `xml:"hyph,omitempty"`
+		Prons []teiPron `xml:"pron"`
+	}
+
+	teiPron struct {
+		Notation string `xml:"notation,attr,omitempty"`
+		Text     string `xml:",chardata"`
+	}
+
+	teiGramGrp struct {
+		Grams []teiGram `xml:"gram"`
+	}
+
+	teiGram struct {
+		Type string `xml:"type,attr"`
+		Norm string `xml:"norm,attr,omitempty"`
+		Text string `xml:",chardata"`
+	}
+
+	teiUsage struct {
+		Type string `xml:"type,attr"`
+		Text string `xml:",chardata"`
+	}
+
+	teiSense struct {
+		ID     string     `xml:"http://www.w3.org/XML/1998/namespace id,attr"`
+		N      string     `xml:"n,attr,omitempty"`
+		Usages []teiUsage `xml:"usg"`
+		Def    string     `xml:"def,omitempty"`
+		Cits   []teiCit   `xml:"cit"`
+		Senses []teiSense `xml:"sense"`
+	}
+
+	teiCit struct {
+		Type  string `xml:"type,attr"`
+		Quote string `xml:"quote"`
+		Note  string `xml:"note,omitempty"`
+	}
+
+	teiEtym struct {
+		Text    string      `xml:",chardata"`
+		Etymons []teiEtymon `xml:"cit"`
+	}
+
+	teiEtymon struct {
+		Type string `xml:"type,attr"`
+		Lang string `xml:"lang,omitempty"`
+		Orth string `xml:"form>orth"`
+	}
+
+	teiXr struct {
+		Type    string   `xml:"type,attr"`
+		Subtype string   `xml:"subtype,attr,omitempty"`
+		Refs    []teiRef `xml:"ref"`
+	}
+
+	teiRef struct {
+		Type   string `xml:"type,attr,omitempty"`
+		Target string `xml:"target,attr,omitempty"`
+		Text   string `xml:",chardata"`
+	}
+)
+
+// TEIWriter writes words as entries of a TEI Lex-0 dictionary
+type TEIWriter struct {
+	out     io.Writer
+	encoder *xml.Encoder
+	ids     map[string]int // entry IDs already used, to keep xml:id unique
+}
+
+// NewTEIWriter creates a TEIWriter and writes the TEI header
+func NewTEIWriter(w io.Writer, title string) (*TEIWriter, error) {
+	if title == "" {
+		title = "Goden Crawler Dictionary"
+	}
+
+	today := time.Now().Format("2006-01-02")
+	if _, err := fmt.Fprintf(w, teiHeader, html.EscapeString(title), today, today); err != nil {
+		return nil, err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("      ", "  ")
+	return &TEIWriter{out: w, encoder: encoder, ids: make(map[string]int)}, nil
+}
+
+// Write implements Writer
+func (t *TEIWriter) Write(word *models.Word) error {
+	entry := t.entry(word)
+	if err := t.encoder.Encode(entry); err != nil {
+		return err
+	}
+	return t.encoder.Flush()
+}
+
+// Close implements Writer
+func (t *TEIWriter) Close() error {
+	_, err := io.WriteString(t.out, teiFooter)
+	return err
+}
+
+// uniqueID returns id, or id with a numeric suffix if it was used before
+func (t *TEIWriter) uniqueID(id string) string {
+	t.ids[id]++
+	if count := t.ids[id]; count > 1 {
+		return fmt.Sprintf("%s_%d", id, count)
+	}
+	return id
+}
+
+// entry maps a word to a TEI Lex-0 entry
+func (t *TEIWriter) entry(word *models.Word) teiEntry {
+	id := t.uniqueID(entryID(word))
+	entry := teiEntry{ID: id, Lang: "de"}
+
+	lemma := teiForm{Type: "lemma", Orth: word.Word}
+	if word.Spelling != nil {
+		lemma.Hyph = word.Spelling.SyllabicDivision
+	}
+	for _, pronunciation := range word.Pronunciation {
+		if pronunciation.Phonetic != "" {
+			lemma.Prons = append(lemma.Prons, teiPron{Notation: "ipa", Text: pronunciation.Phonetic})
+		}
+	}
+	entry.Forms = append(entry.Forms, lemma)
+	for _, form := range word.InflectedForms {
+		if form != word.Word {
+			entry.Forms = append(entry.Forms, teiForm{Type: "inflected", Orth: form})
+		}
+	}
+
+	var grams []teiGram
+	if label, pos, ok := wordPOS(word); ok {
+		grams = append(grams, teiGram{Type: "pos", Norm: pos.ud, Text: label})
+	}
+	if gender := wordGender(word); gender != "" {
+		grams = append(grams, teiGram{Type: "gender", Norm: gender, Text: gender})
+	}
+	if len(grams) > 0 {
+		entry.GramGrp = &teiGramGrp{Grams: grams}
+	}
+	if word.Frequency != "" {
+		entry.Usages = append(entry.Usages, teiUsage{Type: "frequency", Text: word.Frequency})
+	}
+
+	for i, meaning := range word.Meanings {
+		sense, idioms := teiSenseOf(meaning, id, senseNumber(meaning, fmt.Sprint(i+1)))
+		entry.Senses = append(entry.Senses, sense)
+		entry.Related = append(entry.Related, idioms...)
+	}
+
+	if word.Etymology != nil && word.Etymology.Text != "" {
+		etym := &teiEtym{Text: collapseSpace(word.Etymology.Text)}
+		for _, source := range word.Etymology.Sources {
+			etym.Etymons = append(etym.Etymons, teiEtymon{Type: "etymon", Lang: source.Language, Orth: source.Word})
+		}
+		entry.Etym = etym
+	}
+
+	var synonyms []models.RelatedWord
+	for _, synonym := range word.Synonyms {
+		synonyms = append(synonyms, models.RelatedWord{Text: synonym.Text, Link: synonym.Link})
+	}
+	entry.Xrs = appendXr(entry.Xrs, "synonymy", "", synonyms)
+	entry.Xrs = appendXr(entry.Xrs, "antonymy", "", word.Antonyms)
+	var collocations []models.RelatedWord
+	for _, collocation := range word.Collocations {
+		collocations = append(collocations, models.RelatedWord{Text: collocation.Text, Link: collocation.Link})
+	}
+	entry.Xrs = appendXr(entry.Xrs, "related", "collocation", collocations)
+	entry.Xrs = appendXr(entry.Xrs, "related", "compound", word.Compounds)
+	entry.Xrs = appendXr(entry.Xrs, "related", "wordFamily", word.WordFamily)
+
+	if word.SourceURL != "" {
+		entry.Source = &teiRef{Type: "source", Target: word.SourceURL, Text: word.SourceURL}
+	}
+	return entry
+}
+
+// teiSenseOf maps a meaning and its sub-meanings to senses. Idioms become
+// related entries of type "relatedEntry", as Lex-0 encodes multi-word expressions.
+func teiSenseOf(meaning models.Meaning, parentID, number string) (teiSense, []teiEntry) {
+	id := parentID + "." + entryID(&models.Word{ID: "sense" + number})
+	sense := teiSense{ID: id, N: number, Def: collapseSpace(meaning.Text), Usages: teiUsages(meaning.Labels)}
+	if meaning.Grammar != "" {
+		sense.Usages = append(sense.Usages, teiUsage{Type: "hint", Text: meaning.Grammar})
+	}
+	for _, example := range meaning.Examples {
+		sense.Cits = append(sense.Cits, teiCit{Type: "example", Quote: collapseSpace(example.Text), Note: example.Gloss})
+	}
+
+	var related []teiEntry
+	for i, idiom := range meaning.Idioms {
+		idiomID := fmt.Sprintf("%s.idiom%d", id, i+1)
+		idiomEntry := teiEntry{
+			ID:     idiomID,
+			Type:   "relatedEntry",
+			Forms:  []teiForm{{Type: "lemma", Orth: idiom.Text}},
+			Usages: teiUsages(idiom.Labels),
+		}
+		if idiom.Explanation != "" {
+			idiomEntry.Senses = []teiSense{{ID: idiomID + ".1", Def: collapseSpace(idiom.Explanation)}}
+		}
+		related = append(related, idiomEntry)
+	}
+
+	for i, sub := range meaning.SubMeanings {
+		subSense, subIdioms := teiSenseOf(sub, parentID, subSenseNumber(number, i, sub))
+		sense.Senses = append(sense.Senses, subSense)
+		related = append(related, subIdioms...)
+	}
+	return sense, related
+}
+
+// teiUsages maps usage labels to <usg> elements
+func teiUsages(labels models.Labels) []teiUsage {
+	var usages []teiUsage
+	for _, register := range labels.Register {
+		usages = append(usages, teiUsage{Type: "socioCultural", Text: register})
+	}
+	for _, domain := range labels.Domain {
+		usages = append(usages, teiUsage{Type: "domain", Text: domain})
+	}
+	if labels.Figurative {
+		usages = append(usages, teiUsage{Type: "meaning", Text: "figurative"})
+	}
+	return usages
+}
+
+// appendXr adds a cross-reference group for related words
+func appendXr(xrs []teiXr, xrType, subtype string, words []models.RelatedWord) []teiXr {
+	if len(words) == 0 {
+		return xrs
+	}
+
+	xr := teiXr{Type: xrType, Subtype: subtype}
+	for _, word := range words {
+		xr.Refs = append(xr.Refs, teiRef{Type: "entry", Target: absoluteLink(word.Link), Text: word.Text})
+	}
+	return append(xrs, xr)
+}
+
+// dudenSite is the origin of the crawled entries
+const dudenSite = "https://www.duden.de"
+
+// absoluteLink resolves relative Duden links such as "/rechtschreibung/Haus"
+func absoluteLink(link string) string {
+	if strings.HasPrefix(link, "/") {
+		return dudenSite + link
+	}
+	return link
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// teiHomonyms are two homonyms of "Bank" covering the parts of an entry
+// exported to TEI: forms, grammar, labels, numbered senses with sub-senses,
+// examples, idioms, etymology and cross-references
+func teiHomonyms() []*models.Word {
+	return []*models.Word{
+		{
+			ID:             "Bank_Sitzgelegenheit",
+			SourceURL:      "https://www.duden.de/rechtschreibung/Bank_Sitzgelegenheit",
+			Word:           "Bank",
+			Article:        "die",
+			WordType:       []string{"Substantiv, feminin"},
+			Frequency:      "high",
+			InflectedForms: []string{"Bank", "Bänke"},
+			Pronunciation:  []models.Pronunciation{{Word: "Bank", Phonetic: "baŋk"}},
+			Spelling:       &models.Spelling{SyllabicDivision: "Bank"},
+			Meanings: []models.Meaning{
+				{
+					Number:   "1",
+					Text:     "Sitzgelegenheit aus Holz, Stein o. Ä. für mehrere Personen",
+					Examples: []models.Example{{Text: "sich auf eine Bank setzen"}},
+					Idioms: []models.Idiom{{
+						Text:        "etwas auf die lange Bank schieben",
+						Explanation: "etwas hinauszögern",
+						Labels:      models.Labels{Register: []string{"umgangssprachlich"}},
+					}},
+					SubMeanings: []models.Meaning{
+						{Text: "Sitzbank im Park", Examples: []models.Example{{Text: "auf der Bank im Park"}}},
+						{Text: "Kirchenbank", Labels: models.Labels{Domain: []string{"Religion"}}},
+					},
+				},
+				{
+					Number: "2",
+					Text:   "Werkbank",
+					Labels: models.Labels{Domain: []string{"Handwerk"}},
+				},
+			},
+			Etymology: &models.Etymology{
+				Text:    "mittelhochdeutsch banc, althochdeutsch bank",
+				Sources: []models.EtymologySource{{Word: "banc", Language: "mittelhochdeutsch"}, {Word: "bank", Language: "althochdeutsch"}},
+			},
+			Synonyms: []models.Synonym{{Text: "Sitzbank", Link: "/rechtschreibung/Sitzbank"}},
+		},
+		{
+			ID:        "Bank_Geldinstitut",
+			SourceURL: "https://www.duden.de/rechtschreibung/Bank_Geldinstitut",
+			Word:      "Bank",
+			Article:   "die",
+			WordType:  []string{"Substantiv, feminin"},
+			Meanings: []models.Meaning{{
+				Number:   "1",
+				Text:     "Unternehmen, das Geld- und Kreditgeschäfte betreibt",
+				Examples: []models.Example{{Text: "Geld auf der Bank haben", Gloss: "Geld gespart haben"}},
+			}},
+			Etymology: &models.Etymology{
+				Text:    "italienisch banco",
+				Sources: []models.EtymologySource{{Word: "banco", Language: "italienisch"}},
+			},
+			Compounds: []models.RelatedWord{{Text: "Bankkonto", Link: "/rechtschreibung/Bankkonto"}},
+		},
+	}
+}
+
+// TestTEIWriterGolden compares the TEI export of two homonyms with
+// testdata/tei_homonyms.xml and validates it against the TEI Lex-0 schema.
+// Run with -update to rewrite the golden file after intended changes.
+func TestTEIWriterGolden(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewTEIWriter(&buf, "Golden Test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range teiHomonyms() {
+		if err := writer.Write(word); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The header carries the export date
+	got := strings.ReplaceAll(buf.String(), time.Now().Format("2006-01-02"), "2000-01-01")
+
+	golden := filepath.Join("testdata", "tei_homonyms.xml")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("TEI export differs from %s (run with -update after intended changes):\n%s", golden, got)
+	}
+
+	validateLex0(t, golden)
+}
+
+// validateLex0 validates a TEI file against testdata/lex0.rng with xmllint
+func validateLex0(t *testing.T, file string) {
+	t.Helper()
+
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed, skipping TEI Lex-0 validation")
+	}
+	output, err := exec.Command(xmllint, "--noout", "--relaxng", filepath.Join("testdata", "lex0.rng"), file).CombinedOutput()
+	if err != nil {
+		t.Errorf("%s is not valid TEI Lex-0: %v\n%s", file, err, output)
+	}
+}
@@ -2,28 +2,21 @@
 package formatter
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 )
 
-// FormatOutput formats the output based on user selection
-func FormatOutput(wordData *models.Word, format string) (string, error) {
-	switch format {
-	case "json":
-		jsonData, err := json.MarshalIndent(wordData, "", "  ")
-		if err != nil {
-			return "", err
-		}
-		return string(jsonData), nil
-	case "text":
-		return formatAsText(wordData), nil
-	default:
-		return "", errors.New("invalid format selected")
+// sortedSlugs returns the slugs of entries in alphabetical order
+func sortedSlugs(entries map[string]*models.Word) []string {
+	slugs := make([]string, 0, len(entries))
+	for slug := range entries {
+		slugs = append(slugs, slug)
 	}
+	sort.Strings(slugs)
+	return slugs
 }
 
 // formatAsText formats the word data as human-readable text
@@ -32,6 +25,9 @@ func formatAsText(wordData *models.Word) string {
 
 	// General information
 	sb.WriteString(fmt.Sprintf("Word: %s\n", wordData.Word))
+	if wordData.Lookup != nil && wordData.Lookup.Form != wordData.Word {
+		sb.WriteString(fmt.Sprintf("Lemma of: %s (%s)\n", wordData.Lookup.Form, wordData.Lookup.Method))
+	}
 	if wordData.Article != "" {
 		sb.WriteString(fmt.Sprintf("Article: %s\n", wordData.Article))
 	}
@@ -44,14 +40,19 @@ func formatAsText(wordData *models.Word) string {
 	if wordData.Grammar != "" {
 		sb.WriteString(fmt.Sprintf("Grammar: %s\n", wordData.Grammar))
 	}
+	if len(wordData.InflectedForms) > 0 {
+		sb.WriteString(fmt.Sprintf("Forms: %s\n", strings.Join(wordData.InflectedForms, ", ")))
+	}
 
 	// Pronunciation
 	if len(wordData.Pronunciation) > 0 {
 		sb.WriteString("\nPronunciation:\n")
 		for _, p := range wordData.Pronunciation {
 			sb.WriteString(fmt.Sprintf("  Word: %s\n", p.Word))
-			sb.WriteString(fmt.Sprintf("  Phonetic: %s\n", p.Phonetic))
-			if p.Audio != "" && p.Audio != "no_audio_available" {
+			if p.Phonetic != "" {
+				sb.WriteString(fmt.Sprintf("  Phonetic: %s\n", p.Phonetic))
+			}
+			if p.Audio != "" {
 				sb.WriteString(fmt.Sprintf("  Audio: %s\n", p.Audio))
 			}
 		}
@@ -61,56 +62,53 @@ func formatAsText(wordData *models.Word) string {
 	if len(wordData.Meanings) > 0 {
 		sb.WriteString("\nMeanings:\n")
 		for i, meaning := range wordData.Meanings {
-			sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, meaning.Text))
-
-			if meaning.Grammar != "" && meaning.Grammar != "n/a" {
-				sb.WriteString(fmt.Sprintf("   Grammar: %s\n", meaning.Grammar))
-			}
-
-			if len(meaning.Examples) > 0 {
-				sb.WriteString("   Examples:\n")
-				for _, example := range meaning.Examples {
-					sb.WriteString(fmt.Sprintf("     - %s\n", example))
-				}
-			}
+			writeMeaning(&sb, meaning, fmt.Sprintf("%d", i+1), 0)
+		}
+	}
 
-			if len(meaning.Idioms) > 0 {
-				sb.WriteString("   Idioms:\n")
-				for _, idiom := range meaning.Idioms {
-					sb.WriteString(fmt.Sprintf("     - %s\n", idiom))
-				}
+	// Synonyms, grouped by sense when the synonyms page was available
+	if len(wordData.SynonymGroups) > 0 {
+		sb.WriteString("\nSynonyms:\n")
+		for _, group := range wordData.SynonymGroups {
+			texts := make([]string, 0, len(group.Synonyms))
+			for _, synonym := range group.Synonyms {
+				texts = append(texts, formatPhrase(synonym.Text, "", synonym.Labels))
 			}
-
-			if len(meaning.SubMeanings) > 0 {
-				sb.WriteString("   Sub-meanings:\n")
-				for j, subMeaning := range meaning.SubMeanings {
-					sb.WriteString(fmt.Sprintf("     %d.%d. %s\n", i+1, j+1, subMeaning.Text))
-					if len(subMeaning.Examples) > 0 {
-						for _, example := range subMeaning.Examples {
-							sb.WriteString(fmt.Sprintf("       - %s\n", example))
-						}
-					}
-				}
+			if group.Sense != "" {
+				sb.WriteString(fmt.Sprintf("  %s: %s\n", group.Sense, strings.Join(texts, ", ")))
+			} else {
+				sb.WriteString(fmt.Sprintf("  - %s\n", strings.Join(texts, ", ")))
 			}
 		}
-	}
-
-	// Synonyms
-	if len(wordData.Synonyms) > 0 {
+	} else if len(wordData.Synonyms) > 0 {
 		sb.WriteString("\nSynonyms:\n")
 		for _, synonym := range wordData.Synonyms {
-			sb.WriteString(fmt.Sprintf("  - %s\n", synonym.Text))
+			sb.WriteString(fmt.Sprintf("  - %s\n", formatPhrase(synonym.Text, "", synonym.Labels)))
+		}
+	}
+
+	// Related words
+	writeRelatedWords(&sb, "Antonyms", wordData.Antonyms)
+	if len(wordData.Collocations) > 0 {
+		sb.WriteString("\nTypical Collocations:\n")
+		for _, collocation := range wordData.Collocations {
+			sb.WriteString(fmt.Sprintf("  - %s (%.0f%%)\n", collocation.Text, collocation.Strength*100))
 		}
 	}
+	writeRelatedWords(&sb, "Compounds", wordData.Compounds)
+	writeRelatedWords(&sb, "Word Family", wordData.WordFamily)
 
 	// Origin
-	if len(wordData.Origin) > 0 {
+	if wordData.Etymology != nil {
 		sb.WriteString("\nOrigin:\n")
-		var originText string
-		for _, origin := range wordData.Origin {
-			originText += origin.Word + " "
+		sb.WriteString(fmt.Sprintf("  %s\n", wordData.Etymology.Text))
+		for _, source := range wordData.Etymology.Sources {
+			if source.Language != "" {
+				sb.WriteString(fmt.Sprintf("  - %s (%s)\n", source.Word, source.Language))
+			} else {
+				sb.WriteString(fmt.Sprintf("  - %s\n", source.Word))
+			}
 		}
-		sb.WriteString(fmt.Sprintf("  %s\n", strings.TrimSpace(originText)))
 	}
 
 	// Fun Facts
@@ -123,3 +121,76 @@ func formatAsText(wordData *models.Word) string {
 
 	return sb.String()
 }
+
+// writeMeaning writes a meaning and all of its sub-meanings, indenting each level
+func writeMeaning(sb *strings.Builder, meaning models.Meaning, fallbackNumber string, depth int) {
+	number := meaning.Number
+	if number == "" {
+		number = fallbackNumber
+	}
+
+	indent := strings.Repeat("   ", depth)
+	detailIndent := indent + strings.Repeat(" ", len(number)+2)
+
+	sb.WriteString(strings.TrimRight(fmt.Sprintf("%s%s. %s", indent, number, meaning.Text), " ") + "\n")
+
+	if labels := formatLabels(meaning.Labels); labels != "" {
+		sb.WriteString(fmt.Sprintf("%sUsage: %s\n", detailIndent, labels))
+	}
+
+	if meaning.Grammar != "" {
+		sb.WriteString(fmt.Sprintf("%sGrammar: %s\n", detailIndent, meaning.Grammar))
+	}
+
+	if len(meaning.Examples) > 0 {
+		sb.WriteString(fmt.Sprintf("%sExamples:\n", detailIndent))
+		for _, example := range meaning.Examples {
+			sb.WriteString(fmt.Sprintf("%s  - %s\n", detailIndent, formatPhrase(example.Text, example.Gloss, example.Labels)))
+		}
+	}
+
+	if len(meaning.Idioms) > 0 {
+		sb.WriteString(fmt.Sprintf("%sIdioms:\n", detailIndent))
+		for _, idiom := range meaning.Idioms {
+			sb.WriteString(fmt.Sprintf("%s  - %s\n", detailIndent, formatPhrase(idiom.Text, idiom.Explanation, idiom.Labels)))
+		}
+	}
+
+	for j, subMeaning := range meaning.SubMeanings {
+		writeMeaning(sb, subMeaning, models.SubMeaningNumber(number, j, depth+1), depth+1)
+	}
+}
+
+// formatLabels joins register, domain and figurative labels for display
+func formatLabels(labels models.Labels) string {
+	parts := append([]string{}, labels.Register...)
+	parts = append(parts, labels.Domain...)
+	if labels.Figurative {
+		parts = append(parts, "figurative")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeRelatedWords writes a titled, comma-separated list of related words
+func writeRelatedWords(sb *strings.Builder, title string, words []models.RelatedWord) {
+	if len(words) == 0 {
+		return
+	}
+
+	texts := make([]string, 0, len(words))
+	for _, word := range words {
+		texts = append(texts, word.Text)
+	}
+	sb.WriteString(fmt.Sprintf("\n%s:\n  %s\n", title, strings.Join(texts, ", ")))
+}
+
+// formatPhrase renders an example or idiom as "(labels) text – explanation"
+func formatPhrase(text, explanation string, labels models.Labels) string {
+	if l := formatLabels(labels); l != "" {
+		text = fmt.Sprintf("(%s) %s", l, text)
+	}
+	if explanation != "" {
+		text = fmt.Sprintf("%s – %s", text, explanation)
+	}
+	return text
+}
@@ -0,0 +1,250 @@
+// File: internal/formatter/registry.go
+
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/domain/interfaces"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// Registration describes an output format
+type Registration struct {
+	Name        string
+	Description string
+	Extension   string // file extension including the dot, e.g. ".json"
+	MIMEType    string
+	Formatter   interfaces.FormatterService
+}
+
+// EntriesFormatter is implemented by formatters that render all homonym
+// entries of a word together, e.g. as one JSON object or one table.
+// Other formatters render each entry separately.
+type EntriesFormatter interface {
+	FormatEntries(entries map[string]*models.Word, format string) (string, error)
+}
+
+// Registry holds the output formats that --output flags resolve against.
+// Built-in formats and formats provided by plugins are registered side by side.
+type Registry struct {
+	formats map[string]Registration
+	mutex   sync.RWMutex
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		formats: make(map[string]Registration),
+	}
+}
+
+// Register adds a format. Names are case-insensitive and must be unique.
+func (r *Registry) Register(registration Registration) error {
+	name := strings.ToLower(registration.Name)
+	if name == "" || registration.Formatter == nil {
+		return fmt.Errorf("format registration needs a name and a formatter")
+	}
+	if registration.Extension == "" {
+		registration.Extension = "." + name
+	}
+	if registration.MIMEType == "" {
+		registration.MIMEType = "text/plain"
+	}
+	registration.Name = name
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.formats[name]; exists {
+		return fmt.Errorf("format already registered: %s", name)
+	}
+	r.formats[name] = registration
+	return nil
+}
+
+// Get returns the registration of a format
+func (r *Registry) Get(name string) (Registration, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	registration, exists := r.formats[strings.ToLower(name)]
+	return registration, exists
+}
+
+// Names returns the names of all registered formats in alphabetical order
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.formats))
+	for name := range r.formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns all registrations ordered by name
+func (r *Registry) All() []Registration {
+	names := r.Names()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	registrations := make([]Registration, 0, len(names))
+	for _, name := range names {
+		registrations = append(registrations, r.formats[name])
+	}
+	return registrations
+}
+
+// setExtension changes the file extension of a registered format
+func (r *Registry) setExtension(name, extension string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if registration, exists := r.formats[name]; exists {
+		registration.Extension = extension
+		r.formats[name] = registration
+	}
+}
+
+// Format renders a word in the named format
+func (r *Registry) Format(wordData *models.Word, format string) (string, error) {
+	registration, exists := r.Get(format)
+	if !exists {
+		return "", r.unknownFormat(format)
+	}
+	return registration.Formatter.FormatOutput(wordData, registration.Name)
+}
+
+// FormatEntries renders all entries of a homonym, keyed by Duden URL slug.
+// Formatters that do not implement EntriesFormatter render each entry in slug order.
+func (r *Registry) FormatEntries(entries map[string]*models.Word, format string) (string, error) {
+	registration, exists := r.Get(format)
+	if !exists {
+		return "", r.unknownFormat(format)
+	}
+	if formatter, ok := registration.Formatter.(EntriesFormatter); ok {
+		return formatter.FormatEntries(entries, registration.Name)
+	}
+
+	outputs := make([]string, 0, len(entries))
+	for _, slug := range sortedSlugs(entries) {
+		output, err := registration.Formatter.FormatOutput(entries[slug], registration.Name)
+		if err != nil {
+			return "", err
+		}
+		outputs = append(outputs, output)
+	}
+	return strings.Join(outputs, "\n"), nil
+}
+
+// unknownFormat returns an error listing the available formats
+func (r *Registry) unknownFormat(format string) error {
+	return fmt.Errorf("invalid format selected: %q (available: %s)", format, strings.Join(r.Names(), ", "))
+}
+
+// defaultRegistry holds the built-in formats and those registered by plugins
+var defaultRegistry = newBuiltinRegistry()
+
+// Default returns the registry used by FormatOutput and FormatEntries
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// newBuiltinRegistry creates a registry with the formats shipped with the crawler
+func newBuiltinRegistry() *Registry {
+	registry := NewRegistry()
+	for _, registration := range []Registration{
+		{Name: "text", Description: "Human-readable text", Extension: ".txt", MIMEType: "text/plain; charset=utf-8", Formatter: textFormatter{}},
+		{Name: "json", Description: "Indented JSON", Extension: ".json", MIMEType: "application/json", Formatter: jsonFormatter{}},
+		{Name: "csv", Description: "Comma-separated table (see --columns)", Extension: ".csv", MIMEType: "text/csv", Formatter: tableFormatter{}},
+		{Name: "tsv", Description: "Tab-separated table (see --columns)", Extension: ".tsv", MIMEType: "text/tab-separated-values", Formatter: tableFormatter{}},
+		{Name: "xlsx", Description: "Excel workbook (see --columns)", Extension: ".xlsx", MIMEType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Formatter: tableFormatter{}},
+		{Name: "markdown", Description: "Markdown page (see --template-dir)", Extension: ".md", MIMEType: "text/markdown; charset=utf-8", Formatter: templateFormatter{}},
+		{Name: "html", Description: "HTML page (see --template-dir)", Extension: ".html", MIMEType: "text/html; charset=utf-8", Formatter: templateFormatter{}},
+		{Name: "template", Description: "Custom layout given with --template", Extension: ".txt", MIMEType: "text/plain; charset=utf-8", Formatter: templateFormatter{}},
+	} {
+		if err := registry.Register(registration); err != nil {
+			panic(err)
+		}
+	}
+	return registry
+}
+
+// FormatOutput formats the output based on user selection
+func FormatOutput(wordData *models.Word, format string) (string, error) {
+	return defaultRegistry.Format(wordData, format)
+}
+
+// FormatEntries formats all entries of a homonym, keyed by Duden URL slug.
+// JSON output is a single object keyed by slug; text and tabular output list
+// entries in slug order.
+func FormatEntries(entries map[string]*models.Word, format string) (string, error) {
+	return defaultRegistry.FormatEntries(entries, format)
+}
+
+// textFormatter renders words as human-readable text
+type textFormatter struct{}
+
+// FormatOutput implements interfaces.FormatterService
+func (textFormatter) FormatOutput(wordData *models.Word, format string) (string, error) {
+	return formatAsText(wordData), nil
+}
+
+// FormatEntries implements EntriesFormatter
+func (textFormatter) FormatEntries(entries map[string]*models.Word, format string) (string, error) {
+	var sb strings.Builder
+	for i, slug := range sortedSlugs(entries) {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("=== %s ===\n", slug))
+		sb.WriteString(formatAsText(entries[slug]))
+	}
+	return sb.String(), nil
+}
+
+// jsonFormatter renders words as indented JSON
+type jsonFormatter struct{}
+
+// FormatOutput implements interfaces.FormatterService
+func (jsonFormatter) FormatOutput(wordData *models.Word, format string) (string, error) {
+	jsonData, err := json.MarshalIndent(wordData, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// FormatEntries implements EntriesFormatter
+func (jsonFormatter) FormatEntries(entries map[string]*models.Word, format string) (string, error) {
+	jsonData, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// tableFormatter renders words as csv, tsv or xlsx with the configured columns
+type tableFormatter struct{}
+
+// FormatOutput implements interfaces.FormatterService
+func (tableFormatter) FormatOutput(wordData *models.Word, format string) (string, error) {
+	return formatTable([]*models.Word{wordData}, format)
+}
+
+// FormatEntries implements EntriesFormatter
+func (tableFormatter) FormatEntries(entries map[string]*models.Word, format string) (string, error) {
+	words := make([]*models.Word, 0, len(entries))
+	for _, slug := range sortedSlugs(entries) {
+		words = append(words, entries[slug])
+	}
+	return formatTable(words, format)
+}
@@ -0,0 +1,100 @@
+// File: internal/formatter/stream.go
+
+package formatter
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// WordWriter writes words one at a time in an output format, so that whole
+// repositories can be written without holding them in memory.
+// Close completes the output; it does not close the underlying writer.
+type WordWriter interface {
+	Write(word *models.Word) error
+	Close() error
+}
+
+// NewWordWriter creates a WordWriter for any registered format. Tables get a
+// single header row, JSON is written as one array, and all other formats
+// render each word in turn.
+func NewWordWriter(w io.Writer, format string) (WordWriter, error) {
+	registration, exists := defaultRegistry.Get(format)
+	if !exists {
+		return nil, defaultRegistry.unknownFormat(format)
+	}
+
+	switch {
+	case IsTabular(registration.Name):
+		return NewTableWriter(w, registration.Name, tableColumns)
+	case registration.Name == "json":
+		return &jsonArrayWriter{out: w}, nil
+	}
+	return &renderWriter{out: w, registration: registration}, nil
+}
+
+// jsonArrayWriter writes words as the elements of a JSON array
+type jsonArrayWriter struct {
+	out   io.Writer
+	count int
+}
+
+// Write implements WordWriter
+func (j *jsonArrayWriter) Write(word *models.Word) error {
+	data, err := json.MarshalIndent(word, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	separator := "[\n  "
+	if j.count > 0 {
+		separator = ",\n  "
+	}
+	j.count++
+	if _, err := io.WriteString(j.out, separator); err != nil {
+		return err
+	}
+	_, err = j.out.Write(data)
+	return err
+}
+
+// Close implements WordWriter
+func (j *jsonArrayWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.out, end)
+	return err
+}
+
+// renderWriter writes the output of a formatter for each word, separated by blank lines
+type renderWriter struct {
+	out          io.Writer
+	registration Registration
+	count        int
+}
+
+// Write implements WordWriter
+func (r *renderWriter) Write(word *models.Word) error {
+	output, err := r.registration.Formatter.FormatOutput(word, r.registration.Name)
+	if err != nil {
+		return err
+	}
+
+	if r.count > 0 {
+		if _, err := io.WriteString(r.out, "\n"); err != nil {
+			return err
+		}
+	}
+	r.count++
+	_, err = io.WriteString(r.out, output+"\n")
+	return err
+}
+
+// Close implements WordWriter
+func (r *renderWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,282 @@
+// File: internal/formatter/tabular.go
+
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// listSeparator joins multiple values in one cell
+const listSeparator = "; "
+
+// DefaultColumns are the columns of tabular output unless configured otherwise
+var DefaultColumns = []string{"word", "article", "word_type", "frequency", "plural", "meaning", "example"}
+
+// columns maps column names to the value they take from a word
+var columns = map[string]func(word *models.Word) string{
+	"id":        func(w *models.Word) string { return w.ID },
+	"word":      func(w *models.Word) string { return w.Word },
+	"article":   func(w *models.Word) string { return w.Article },
+	"word_type": func(w *models.Word) string { return strings.Join(w.WordType, listSeparator) },
+	"frequency": func(w *models.Word) string { return w.Frequency },
+	"grammar":   func(w *models.Word) string { return w.Grammar },
+	"plural":    func(w *models.Word) string { return w.Plural() },
+	"forms":     func(w *models.Word) string { return strings.Join(w.InflectedForms, listSeparator) },
+	"meaning": func(w *models.Word) string {
+		if len(w.Meanings) == 0 {
+			return ""
+		}
+		return w.Meanings[0].Text
+	},
+	"meanings": func(w *models.Word) string {
+		var texts []string
+		for i, meaning := range w.Meanings {
+			texts = append(texts, fmt.Sprintf("%d. %s", i+1, meaning.Text))
+		}
+		return strings.Join(texts, listSeparator)
+	},
+	"example": func(w *models.Word) string {
+		if examples := allExamples(w.Meanings); len(examples) > 0 {
+			return examples[0]
+		}
+		return ""
+	},
+	"examples": func(w *models.Word) string { return strings.Join(allExamples(w.Meanings), listSeparator) },
+	"synonyms": func(w *models.Word) string {
+		texts := make([]string, 0, len(w.Synonyms))
+		for _, synonym := range w.Synonyms {
+			texts = append(texts, synonym.Text)
+		}
+		return strings.Join(texts, listSeparator)
+	},
+	"antonyms": func(w *models.Word) string { return relatedTexts(w.Antonyms) },
+	"phonetic": func(w *models.Word) string {
+		for _, pronunciation := range w.Pronunciation {
+			if pronunciation.Phonetic != "" {
+				return pronunciation.Phonetic
+			}
+		}
+		return ""
+	},
+	"syllables": func(w *models.Word) string {
+		if w.Spelling == nil {
+			return ""
+		}
+		return w.Spelling.SyllabicDivision
+	},
+	"origin": func(w *models.Word) string {
+		if w.Etymology == nil {
+			return ""
+		}
+		return w.Etymology.Text
+	},
+	"source_url": func(w *models.Word) string { return w.SourceURL },
+	"fetched_at": func(w *models.Word) string {
+		if w.FetchedAt.IsZero() {
+			return ""
+		}
+		return w.FetchedAt.Format(time.RFC3339)
+	},
+}
+
+// tableColumns are the columns used by FormatOutput and FormatEntries
+var tableColumns = DefaultColumns
+
+// ColumnNames returns the names of all available columns in alphabetical order
+func ColumnNames() []string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseColumns parses a comma-separated column list such as "word,article,plural".
+// An empty list selects DefaultColumns.
+func ParseColumns(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return DefaultColumns, nil
+	}
+
+	var selected []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, exists := columns[name]; !exists {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(ColumnNames(), ", "))
+		}
+		selected = append(selected, name)
+	}
+	return selected, nil
+}
+
+// SetColumns sets the columns of csv, tsv and xlsx output produced by FormatOutput and FormatEntries
+func SetColumns(selected []string) {
+	tableColumns = selected
+}
+
+// IsTabular reports whether a format writes a table
+func IsTabular(format string) bool {
+	switch format {
+	case "csv", "tsv", "xlsx":
+		return true
+	}
+	return false
+}
+
+// NewTableWriter creates a WordWriter for "csv", "tsv" or "xlsx" output that
+// writes words as rows of a table. The header row is written before the first
+// word; Close must be called to complete the output.
+func NewTableWriter(w io.Writer, format string, selected []string) (WordWriter, error) {
+	switch format {
+	case "csv":
+		return newDelimitedWriter(w, ',', selected)
+	case "tsv":
+		return newDelimitedWriter(w, '\t', selected)
+	case "xlsx":
+		return newXLSXWriter(w, selected)
+	default:
+		return nil, fmt.Errorf("not a tabular format: %s", format)
+	}
+}
+
+// formatTable renders words with the configured columns
+func formatTable(words []*models.Word, format string) (string, error) {
+	var buf bytes.Buffer
+	writer, err := NewTableWriter(&buf, format, tableColumns)
+	if err != nil {
+		return "", err
+	}
+
+	for _, word := range words {
+		if err := writer.Write(word); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// row returns the values of the selected columns for a word
+func row(word *models.Word, selected []string) []string {
+	values := make([]string, len(selected))
+	for i, name := range selected {
+		values[i] = columns[name](word)
+	}
+	return values
+}
+
+// delimitedWriter writes CSV or TSV
+type delimitedWriter struct {
+	writer   *csv.Writer
+	selected []string
+}
+
+func newDelimitedWriter(w io.Writer, delimiter rune, selected []string) (*delimitedWriter, error) {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	if err := writer.Write(selected); err != nil {
+		return nil, err
+	}
+	return &delimitedWriter{writer: writer, selected: selected}, nil
+}
+
+// Write implements TableWriter
+func (d *delimitedWriter) Write(word *models.Word) error {
+	return d.writer.Write(row(word, d.selected))
+}
+
+// Close implements TableWriter
+func (d *delimitedWriter) Close() error {
+	d.writer.Flush()
+	return d.writer.Error()
+}
+
+// xlsxWriter streams rows into a single worksheet and writes the workbook on Close
+type xlsxWriter struct {
+	out      io.Writer
+	file     *excelize.File
+	stream   *excelize.StreamWriter
+	selected []string
+	rows     int
+}
+
+func newXLSXWriter(w io.Writer, selected []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the header visible while scrolling
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return nil, err
+	}
+
+	x := &xlsxWriter{out: w, file: file, stream: stream, selected: selected}
+	if err := x.writeRow(selected); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// writeRow appends a row of cells
+func (x *xlsxWriter) writeRow(values []string) error {
+	x.rows++
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, x.rows)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, cells)
+}
+
+// Write implements TableWriter
+func (x *xlsxWriter) Write(word *models.Word) error {
+	return x.writeRow(row(word, x.selected))
+}
+
+// Close implements TableWriter
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// allExamples returns the example texts of a meaning tree in order
+func allExamples(meanings []models.Meaning) []string {
+	var texts []string
+	for _, meaning := range meanings {
+		texts = append(texts, meaning.ExampleTexts()...)
+		texts = append(texts, allExamples(meaning.SubMeanings)...)
+	}
+	return texts
+}
+
+// relatedTexts joins the texts of related words
+func relatedTexts(words []models.RelatedWord) string {
+	texts := make([]string, 0, len(words))
+	for _, word := range words {
+		texts = append(texts, word.Text)
+	}
+	return strings.Join(texts, listSeparator)
+}
@@ -0,0 +1,208 @@
+// File: internal/formatter/template.go
+
+package formatter
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// dudenBaseURL resolves relative Duden links such as "/rechtschreibung/Haus"
+const dudenBaseURL = "https://www.duden.de"
+
+// builtinTemplates holds the default layouts of the markdown and html formats
+//
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// templateFiles maps template-based formats to their layout file name.
+// A file of the same name in the override directory replaces the built-in layout.
+var templateFiles = map[string]string{
+	"markdown": "word.md.tmpl",
+	"html":     "word.html.tmpl",
+}
+
+// Page is the data passed to templates. Single words are rendered as a page
+// with one word; homonym entries as a page with all of them.
+type Page struct {
+	Title string
+	Words []*models.Word
+}
+
+// executor is implemented by both text/template and html/template templates
+type executor interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+var (
+	// templates holds the parsed layout of every template-based format
+	templates      = make(map[string]executor)
+	templatesMutex sync.RWMutex
+)
+
+// templateFuncs are available in all templates
+var templateFuncs = map[string]interface{}{
+	"join":     strings.Join,
+	"labels":   formatLabels,
+	"dudenURL": dudenURL,
+	"indent":   func(depth int) string { return strings.Repeat("    ", depth) },
+	"inc":      func(i int) int { return i + 1 },
+	"letter":   func(i int) string { return string(rune('a' + i)) },
+	"percent":  func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"number": func(meaning models.Meaning, index int) string {
+		if meaning.Number != "" {
+			return meaning.Number
+		}
+		return fmt.Sprintf("%d", index+1)
+	},
+	"subNumber": func(sub models.Meaning, parent string, index, depth int) string {
+		if sub.Number != "" {
+			return sub.Number
+		}
+		return models.SubMeaningNumber(parent, index, depth)
+	},
+	"meaning": func(meaning models.Meaning, number string, depth int) meaningContext {
+		return meaningContext{Meaning: meaning, Number: number, Depth: depth}
+	},
+}
+
+// meaningContext is passed to the recursive "meaning" template
+type meaningContext struct {
+	Meaning models.Meaning
+	Number  string
+	Depth   int
+}
+
+// dudenURL makes a Duden link absolute
+func dudenURL(link string) string {
+	if strings.HasPrefix(link, "/") {
+		return dudenBaseURL + link
+	}
+	return link
+}
+
+// isHTMLTemplate reports whether a layout file produces HTML and must be escaped accordingly
+func isHTMLTemplate(file string) bool {
+	return strings.Contains(strings.ToLower(filepath.Base(file)), ".htm")
+}
+
+// parseTemplate parses a layout with html/template for HTML files and text/template otherwise
+func parseTemplate(name, source string, html bool) (executor, error) {
+	if html {
+		return htmltemplate.New(name).Funcs(htmltemplate.FuncMap(templateFuncs)).Parse(source)
+	}
+	return texttemplate.New(name).Funcs(texttemplate.FuncMap(templateFuncs)).Parse(source)
+}
+
+// LoadTemplates parses the layouts of the markdown and html formats. Files named
+// word.md.tmpl or word.html.tmpl in overrideDir replace the built-in layouts;
+// an empty overrideDir uses the built-in layouts only.
+func LoadTemplates(overrideDir string) error {
+	parsed := make(map[string]executor, len(templateFiles))
+
+	for format, file := range templateFiles {
+		source, err := builtinTemplates.ReadFile("templates/" + file)
+		if err != nil {
+			return err
+		}
+
+		if overrideDir != "" {
+			override, err := os.ReadFile(filepath.Join(overrideDir, file))
+			if err == nil {
+				source = override
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		tmpl, err := parseTemplate(file, string(source), isHTMLTemplate(file))
+		if err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", file, err)
+		}
+		parsed[format] = tmpl
+	}
+
+	templatesMutex.Lock()
+	defer templatesMutex.Unlock()
+	for format, tmpl := range parsed {
+		templates[format] = tmpl
+	}
+	return nil
+}
+
+// SetCustomTemplate uses a layout file for the "template" format. Files whose
+// name contains ".htm" are parsed with html/template; the output file extension
+// is taken from the file name without ".tmpl" (wiki.md.tmpl -> ".md").
+func SetCustomTemplate(file string) error {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := parseTemplate(filepath.Base(file), string(source), isHTMLTemplate(file))
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", file, err)
+	}
+
+	templatesMutex.Lock()
+	templates["template"] = tmpl
+	templatesMutex.Unlock()
+
+	if ext := filepath.Ext(strings.TrimSuffix(file, ".tmpl")); ext != "" {
+		defaultRegistry.setExtension("template", ext)
+	}
+	return nil
+}
+
+// templateFormatter renders words with the layout registered for its format
+type templateFormatter struct{}
+
+// FormatOutput implements interfaces.FormatterService
+func (templateFormatter) FormatOutput(wordData *models.Word, format string) (string, error) {
+	return renderTemplate(format, Page{Title: wordData.Word, Words: []*models.Word{wordData}})
+}
+
+// FormatEntries implements EntriesFormatter
+func (templateFormatter) FormatEntries(entries map[string]*models.Word, format string) (string, error) {
+	page := Page{}
+	for _, slug := range sortedSlugs(entries) {
+		page.Words = append(page.Words, entries[slug])
+	}
+	if len(page.Words) > 0 {
+		page.Title = page.Words[0].Word
+	}
+	return renderTemplate(format, page)
+}
+
+// renderTemplate executes the layout of a format
+func renderTemplate(format string, page Page) (string, error) {
+	templatesMutex.RLock()
+	tmpl, exists := templates[format]
+	templatesMutex.RUnlock()
+
+	if !exists {
+		if format == "template" {
+			return "", fmt.Errorf("no template given: use --template <file>")
+		}
+		if err := LoadTemplates(""); err != nil {
+			return "", err
+		}
+		return renderTemplate(format, page)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, page); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
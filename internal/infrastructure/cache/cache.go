@@ -102,6 +102,42 @@ func (c *Cache) Set(word string, data *models.Word) {
 	}
 }
 
+// Invalidate removes the entries of a changed word: those cached under its
+// ID or display word and those holding data of the same entry. It returns the
+// number of entries removed from memory.
+func (c *Cache) Invalidate(id, word string) int {
+	keys := []string{id, word}
+
+	removed := 0
+	if c.enableMemory {
+		c.mu.Lock()
+		for key, entry := range c.memoryCache {
+			if key == id || key == word || (entry.Data != nil && id != "" && entry.Data.ID == id) {
+				delete(c.memoryCache, key)
+				keys = append(keys, key)
+				removed++
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	if c.enableDisk && c.cachePath != "" {
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			filePath := filepath.Join(c.cachePath, fmt.Sprintf("%s.json", key))
+			if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+				logger.Warn("Failed to remove cache file",
+					logger.F("file", filePath),
+					logger.F("error", err))
+			}
+		}
+	}
+
+	return removed
+}
+
 // Clear clears the cache
 func (c *Cache) Clear() error {
 	// Clear memory cache
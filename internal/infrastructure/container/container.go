@@ -3,13 +3,18 @@
 package container
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/amirhossein-jamali/goden-crawler/internal/application/services"
 	"github.com/amirhossein-jamali/goden-crawler/internal/crawler"
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/cache"
+	"github.com/amirhossein-jamali/goden-crawler/internal/infrastructure/plugins"
+	"github.com/amirhossein-jamali/goden-crawler/internal/media"
+	"github.com/amirhossein-jamali/goden-crawler/internal/outbox"
 	"github.com/amirhossein-jamali/goden-crawler/internal/repository"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
@@ -73,6 +78,18 @@ func (c *Container) GetWordService() *services.WordService {
 	return service.(*services.WordService)
 }
 
+// GetRefreshService returns the RefreshService. It crawls through the
+// uncached scraper, so that refreshed words are fetched from Duden.
+func (c *Container) GetRefreshService() *services.RefreshService {
+	service, _ := c.Get("refreshService")
+	if service == nil {
+		refreshService := services.NewRefreshService(c.GetDudenScraper(), c.GetWordRepository())
+		c.Register("refreshService", refreshService)
+		return refreshService
+	}
+	return service.(*services.RefreshService)
+}
+
 // GetDudenScraper returns the DudenScraper
 func (c *Container) GetDudenScraper() *crawler.DudenScraper {
 	service, _ := c.Get("dudenScraper")
@@ -114,13 +131,61 @@ func (c *Container) GetCachedDudenScraper() *crawler.CachedDudenScraper {
 func (c *Container) GetWordRepository() *repository.WordRepository {
 	service, _ := c.Get("wordRepository")
 	if service == nil {
-		wordRepo := repository.NewWordRepository()
+		wordRepo := newWordRepository()
 		c.Register("wordRepository", wordRepo)
 		return wordRepo
 	}
 	return service.(*repository.WordRepository)
 }
 
+// GetMediaPipeline returns the media pipeline storing files in mediaDir.
+// Downloads share the scraper's rate limiter.
+func (c *Container) GetMediaPipeline(mediaDir string) (*media.Pipeline, error) {
+	service, _ := c.Get("mediaPipeline")
+	if service != nil {
+		return service.(*media.Pipeline), nil
+	}
+
+	store, err := media.NewStore(mediaDir)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := media.NewPipeline(store, c.GetDudenScraper().RateLimiter)
+	c.Register("mediaPipeline", pipeline)
+	return pipeline, nil
+}
+
+// GetPluginManager returns the PluginManager. On first use it registers the
+// bundled example plugin and loads the plugins found in PLUGIN_DIR, so that
+// their formatters are available in the default formatter registry.
+func (c *Container) GetPluginManager() *plugins.PluginManager {
+	service, _ := c.Get("pluginManager")
+	if service != nil {
+		return service.(*plugins.PluginManager)
+	}
+
+	config := utils.LoadConfig()
+	pluginManager := plugins.NewPluginManager(config.PluginDir, formatter.Default())
+	if err := plugins.Init(pluginManager); err != nil {
+		logger.Error("Failed to register bundled plugins", logger.F("error", err))
+	}
+	if config.PluginDir != "" {
+		if err := pluginManager.LoadPlugins(); err != nil {
+			logger.Error("Failed to load plugins", logger.F("error", err))
+		}
+	}
+
+	c.Register("pluginManager", pluginManager)
+	return pluginManager
+}
+
+// GetFormatterRegistry returns the formatter registry with built-in and plugin formats
+func (c *Container) GetFormatterRegistry() *formatter.Registry {
+	c.GetPluginManager()
+	return formatter.Default()
+}
+
 // GetBatchService returns the BatchService
 func (c *Container) GetBatchService() *services.BatchService {
 	service, _ := c.Get("batchService")
@@ -157,13 +222,18 @@ func registerDefaultServices(c *Container) {
 	c.Register("cache", cacheInstance)
 
 	// Register repository
-	wordRepo := repository.NewWordRepository()
+	wordRepo := newWordRepository()
 	c.Register("wordRepository", wordRepo)
 
 	// Register DudenScraper
 	dudenScraper := crawler.NewDudenScraper()
 	c.Register("dudenScraper", dudenScraper)
 
+	// Drop cached words when they are saved, here or by another instance
+	wordRepo.OnInvalidate(func(id, word string) {
+		cacheInstance.Invalidate(id, word)
+	})
+
 	// Register cached scraper
 	cachedDudenScraper := crawler.NewCachedDudenScraper(dudenScraper, cacheInstance)
 	c.Register("cachedDudenScraper", cachedDudenScraper)
@@ -177,6 +247,27 @@ func registerDefaultServices(c *Container) {
 	c.Register("batchService", batchService)
 }
 
+// newWordRepository creates the WordRepository with the outbox configured by
+// OUTBOX. Databases are connected on first use.
+func newWordRepository() *repository.WordRepository {
+	config := utils.LoadConfig()
+	wordRepo := repository.NewWordRepository()
+	if _, err := wordRepo.WithRequiredBackends(config.RequiredBackends); err != nil {
+		logger.Error("Invalid REQUIRED_BACKENDS, requiring MongoDB only", logger.F("error", err))
+	}
+
+	store, err := outbox.NewStore(config.OutboxStore, config.OutboxDir)
+	if err != nil {
+		logger.Error("Invalid outbox configuration, writing synchronously", logger.F("error", err))
+		return wordRepo
+	}
+
+	options := outbox.DefaultOptions()
+	options.Workers = config.OutboxWorkers
+	options.MaxAttempts = config.OutboxMaxAttempts
+	return wordRepo.WithOutbox(store, options)
+}
+
 // Helper functions for getting services
 
 // GetWordService returns the WordService from the singleton container
@@ -184,6 +275,11 @@ func GetWordService() *services.WordService {
 	return GetContainer().GetWordService()
 }
 
+// GetRefreshService returns the RefreshService from the singleton container
+func GetRefreshService() *services.RefreshService {
+	return GetContainer().GetRefreshService()
+}
+
 // GetDudenScraper returns the DudenScraper from the singleton container
 func GetDudenScraper() *crawler.DudenScraper {
 	return GetContainer().GetDudenScraper()
@@ -194,6 +290,23 @@ func GetCachedDudenScraper() *crawler.CachedDudenScraper {
 	return GetContainer().GetCachedDudenScraper()
 }
 
+// GetMediaPipeline returns the media pipeline from the singleton container
+func GetMediaPipeline(mediaDir string) (*media.Pipeline, error) {
+	return GetContainer().GetMediaPipeline(mediaDir)
+}
+
+// GetPluginManager returns the PluginManager from the singleton container,
+// loading the plugins on first use
+func GetPluginManager() *plugins.PluginManager {
+	return GetContainer().GetPluginManager()
+}
+
+// WatchInvalidations keeps the in-memory cache in sync with words saved by
+// other instances until ctx is done. Long-running commands call it.
+func WatchInvalidations(ctx context.Context) {
+	GetContainer().GetWordRepository().WatchInvalidations(ctx)
+}
+
 // GetCache returns the Cache from the singleton container
 func GetCache() *cache.Cache {
 	return GetContainer().GetCache()
@@ -52,7 +52,7 @@ func (e *GeneralInfoExtractor) extractArticle() string {
 func (e *GeneralInfoExtractor) extractWordType() []string {
 	wordTypeText := e.ExtractText(".tuple__key:-soup-contains('Wortart') + .tuple__val", "")
 	if wordTypeText == "" {
-		return []string{}
+		return nil
 	}
 
 	return utils.SplitAndTrim(wordTypeText, ",")
@@ -62,7 +62,7 @@ func (e *GeneralInfoExtractor) extractWordType() []string {
 func (e *GeneralInfoExtractor) extractFrequency() string {
 	frequencyElement := e.Doc.Find(".tuple__key:-soup-contains('Häufigkeit') + .tuple__val .shaft")
 	if frequencyElement.Length() == 0 {
-		return "unknown"
+		return ""
 	}
 
 	frequencyText := frequencyElement.Text()
@@ -80,7 +80,7 @@ func (e *GeneralInfoExtractor) extractFrequency() string {
 	case 1:
 		return "very_low"
 	default:
-		return "unknown"
+		return ""
 	}
 }
 
@@ -105,15 +105,5 @@ func (e *GeneralInfoExtractor) extractPronunciation() []models.Pronunciation {
 		})
 	})
 
-	if len(pronunciations) == 0 {
-		return []models.Pronunciation{
-			{
-				Word:     "",
-				Phonetic: "",
-				Audio:    "",
-			},
-		}
-	}
-
 	return pronunciations
 }
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/amirhossein-jamali/goden-crawler/internal/domain/interfaces"
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
 )
 
@@ -16,19 +17,19 @@ import (
 type PluginManager struct {
 	pluginDir       string
 	extractors      map[string]interfaces.Extractor
-	formatters      map[string]interfaces.FormatterService
+	formatters      *formatter.Registry
 	extractorsMutex sync.RWMutex
-	formattersMutex sync.RWMutex
 	loadedPlugins   map[string]*plugin.Plugin
 	pluginsMutex    sync.RWMutex
 }
 
-// NewPluginManager creates a new plugin manager
-func NewPluginManager(pluginDir string) *PluginManager {
+// NewPluginManager creates a new plugin manager. Formatters registered by
+// plugins are added to the given registry next to the built-in formats.
+func NewPluginManager(pluginDir string, formatters *formatter.Registry) *PluginManager {
 	return &PluginManager{
 		pluginDir:     pluginDir,
 		extractors:    make(map[string]interfaces.Extractor),
-		formatters:    make(map[string]interfaces.FormatterService),
+		formatters:    formatters,
 		loadedPlugins: make(map[string]*plugin.Plugin),
 	}
 }
@@ -111,7 +112,7 @@ func (pm *PluginManager) RegisterExtractor(name string, extractor interfaces.Ext
 	defer pm.extractorsMutex.Unlock()
 
 	pm.extractors[name] = extractor
-	logger.Info("Registered extractor", logger.F("name", name))
+	logger.Debug("Registered extractor", logger.F("name", name))
 }
 
 // GetExtractor retrieves an extractor by name
@@ -137,34 +138,32 @@ func (pm *PluginManager) GetAllExtractors() map[string]interfaces.Extractor {
 	return extractors
 }
 
-// RegisterFormatter registers a formatter
-func (pm *PluginManager) RegisterFormatter(name string, formatter interfaces.FormatterService) {
-	pm.formattersMutex.Lock()
-	defer pm.formattersMutex.Unlock()
+// RegisterFormatter registers a formatter under a format name. Use
+// RegisterFormat to provide a description, file extension and MIME type.
+func (pm *PluginManager) RegisterFormatter(name string, service interfaces.FormatterService) {
+	pm.RegisterFormat(formatter.Registration{Name: name, Formatter: service})
+}
 
-	pm.formatters[name] = formatter
-	logger.Info("Registered formatter", logger.F("name", name))
+// RegisterFormat registers a formatter with its metadata
+func (pm *PluginManager) RegisterFormat(registration formatter.Registration) {
+	if err := pm.formatters.Register(registration); err != nil {
+		logger.Error("Failed to register formatter", logger.F("name", registration.Name), logger.F("error", err))
+		return
+	}
+	logger.Debug("Registered formatter", logger.F("name", registration.Name))
 }
 
 // GetFormatter retrieves a formatter by name
 func (pm *PluginManager) GetFormatter(name string) (interfaces.FormatterService, bool) {
-	pm.formattersMutex.RLock()
-	defer pm.formattersMutex.RUnlock()
-
-	formatter, exists := pm.formatters[name]
-	return formatter, exists
+	registration, exists := pm.formatters.Get(name)
+	return registration.Formatter, exists
 }
 
-// GetAllFormatters returns all registered formatters
+// GetAllFormatters returns all registered formatters, including the built-in ones
 func (pm *PluginManager) GetAllFormatters() map[string]interfaces.FormatterService {
-	pm.formattersMutex.RLock()
-	defer pm.formattersMutex.RUnlock()
-
-	// Create a copy to avoid concurrent map access
-	formatters := make(map[string]interfaces.FormatterService, len(pm.formatters))
-	for name, formatter := range pm.formatters {
-		formatters[name] = formatter
+	formatters := make(map[string]interfaces.FormatterService)
+	for _, registration := range pm.formatters.All() {
+		formatters[registration.Name] = registration.Formatter
 	}
-
 	return formatters
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/amirhossein-jamali/goden-crawler/internal/domain/interfaces"
+	"github.com/amirhossein-jamali/goden-crawler/internal/formatter"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 )
 
@@ -61,7 +62,13 @@ func (e *CustomExtractor) GetName() string {
 // This function is called when the plugin is loaded
 func Init(pm *PluginManager) error {
 	// Register the XML formatter
-	pm.RegisterFormatter("xml", &XMLFormatter{})
+	pm.RegisterFormat(formatter.Registration{
+		Name:        "xml",
+		Description: "XML document (example plugin)",
+		Extension:   ".xml",
+		MIMEType:    "application/xml",
+		Formatter:   &XMLFormatter{},
+	})
 
 	// Register the custom extractor factory
 	pm.RegisterExtractor("custom", &CustomExtractor{
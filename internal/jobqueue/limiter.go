@@ -0,0 +1,83 @@
+// File: internal/jobqueue/limiter.go
+
+package jobqueue
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	dbredis "github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
+	"github.com/go-redis/redis/v8"
+)
+
+// slotScript reserves the next request slot and returns the milliseconds to
+// wait for it. Redis' clock is used, so workers on different hosts agree.
+var slotScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local slot = tonumber(redis.call('GET', KEYS[1]) or '0')
+if slot < now then
+	slot = now
+end
+local delay = tonumber(ARGV[1])
+redis.call('SET', KEYS[1], slot + delay, 'PX', slot - now + delay + 1000)
+return slot - now
+`)
+
+// SharedLimiter is a utils.Limiter shared by all processes using the same
+// Redis server and name: together they make at most one request per interval
+// plus up to jitter. While Redis is unavailable each process falls back to a
+// local limiter with the same settings.
+type SharedLimiter struct {
+	key      string
+	interval time.Duration
+	jitter   time.Duration
+	local    *utils.RateLimiter
+}
+
+// NewSharedLimiter creates a SharedLimiter
+func NewSharedLimiter(name string, interval, jitter time.Duration) *SharedLimiter {
+	return &SharedLimiter{
+		key:      dbredis.Key("ratelimit:" + name),
+		interval: interval,
+		jitter:   jitter,
+		local:    utils.NewRateLimiter(interval, jitter),
+	}
+}
+
+// Wait blocks until the caller may make its next request
+func (l *SharedLimiter) Wait() {
+	delay := l.interval
+	if l.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(l.jitter)))
+	}
+
+	wait, err := l.reserve(delay)
+	if err != nil {
+		logger.Debug("Shared rate limit unavailable, limiting locally", logger.F("error", err))
+		l.local.Wait()
+		return
+	}
+	time.Sleep(wait)
+}
+
+// reserve takes the next slot and returns the time until it starts
+func (l *SharedLimiter) reserve(delay time.Duration) (time.Duration, error) {
+	client, err := dbredis.ConnectRedis()
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wait int64
+	err = dbredis.Do(func() error {
+		var err error
+		wait, err = slotScript.Run(ctx, client, []string{l.key}, delay.Milliseconds()).Int64()
+		return err
+	})
+	return time.Duration(wait) * time.Millisecond, err
+}
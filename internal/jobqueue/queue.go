@@ -0,0 +1,574 @@
+// File: internal/jobqueue/queue.go
+
+// Package jobqueue distributes the words of bulk jobs over any number of
+// worker processes. Words are entries of a Redis stream read through a
+// consumer group: a word read by a worker stays leased to it until the worker
+// completes it, fails it or stops renewing the lease, after which another
+// worker claims it. Failed words are retried with exponential backoff and
+// moved to a dead-letter stream after too many attempts.
+package jobqueue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dbredis "github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/go-redis/redis/v8"
+)
+
+// group is the consumer group all workers belong to
+const group = "workers"
+
+// ErrLeaseLost is returned when renewing the lease of a task another worker has claimed
+var ErrLeaseLost = errors.New("lease lost to another worker")
+
+// Options configures a Queue
+type Options struct {
+	Visibility   time.Duration // time a task stays leased without a renewal before other workers may claim it
+	MaxAttempts  int           // attempts, including expired leases, before a word is dead-lettered
+	RetryBackoff time.Duration // delay before the first retry, doubled on every further attempt
+	MaxBackoff   time.Duration
+	JobRetention time.Duration // time the progress of a job is kept once all its words are done or dead
+}
+
+// DefaultOptions returns the default queue options
+func DefaultOptions() Options {
+	return Options{
+		Visibility:   2 * time.Minute,
+		MaxAttempts:  5,
+		RetryBackoff: 30 * time.Second,
+		MaxBackoff:   30 * time.Minute,
+		JobRetention: 7 * 24 * time.Hour,
+	}
+}
+
+// Task is a word of a job leased to a worker
+type Task struct {
+	ID      string // stream entry ID
+	Job     string
+	Word    string
+	Attempt int // 1 for the first attempt
+}
+
+// Queue is a worker's connection to the shared queue
+type Queue struct {
+	consumer string
+	options  Options
+	mutex    sync.Mutex
+	ready    bool // the consumer group exists
+}
+
+// NewQueue creates a Queue for the named consumer. Consumer names must be
+// unique among running workers. Redis is connected on first use.
+func NewQueue(consumer string, options Options) *Queue {
+	return &Queue{consumer: consumer, options: options}
+}
+
+// Keys of the queue
+func streamKey() string       { return dbredis.Key("queue:words") }
+func delayedKey() string      { return dbredis.Key("queue:delayed") }
+func deadKey() string         { return dbredis.Key("queue:dead") }
+func jobKey(id string) string { return dbredis.Key("job:" + id) }
+
+// client returns the Redis client, creating the consumer group on first use
+func (q *Queue) client(ctx context.Context) (*redis.Client, error) {
+	client, err := dbredis.ConnectRedis()
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.ready {
+		err := dbredis.Do(func() error {
+			return client.XGroupCreateMkStream(ctx, streamKey(), group, "0").Err()
+		})
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return nil, fmt.Errorf("failed to create consumer group: %w", err)
+		}
+		q.ready = true
+	}
+	return client, nil
+}
+
+// newJobID returns a sortable, unique job ID
+func newJobID() string {
+	buf := make([]byte, 3)
+	_, _ = rand.Read(buf)
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(buf)
+}
+
+// Enqueue adds the words of a new job and returns its ID
+func (q *Queue) Enqueue(words []string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	job := newJobID()
+	err = dbredis.Do(func() error {
+		err := client.HSet(ctx, jobKey(job),
+			"total", len(words),
+			"created_at", time.Now().UTC().Format(time.RFC3339)).Err()
+		if err != nil {
+			return err
+		}
+
+		// Add the words in pipelines of bounded size
+		for start := 0; start < len(words); start += 1000 {
+			end := start + 1000
+			if end > len(words) {
+				end = len(words)
+			}
+			pipe := client.Pipeline()
+			for _, word := range words[start:end] {
+				pipe.XAdd(ctx, &redis.XAddArgs{
+					Stream: streamKey(),
+					Values: map[string]interface{}{"job": job, "word": word, "attempt": 1},
+				})
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return job, nil
+}
+
+// promoteScript moves retries that are due from the delayed set back to the stream
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, member in ipairs(due) do
+	redis.call('ZREM', KEYS[1], member)
+	local entry = cjson.decode(member)
+	redis.call('XADD', KEYS[2], '*', 'job', entry.job, 'word', entry.word, 'attempt', entry.attempt)
+end
+return #due
+`)
+
+// Next leases the next task, waiting up to block for one. Tasks whose lease
+// expired are claimed before new ones. It returns nil when no task is available.
+func (q *Queue) Next(ctx context.Context, block time.Duration) (*Task, error) {
+	client, err := q.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var task *Task
+		err := dbredis.Do(func() error {
+			if err := promoteScript.Run(ctx, client, []string{delayedKey(), streamKey()}, time.Now().UnixMilli()).Err(); err != nil && err != redis.Nil {
+				return err
+			}
+
+			var err error
+			if task, err = q.reclaim(ctx, client); err != nil || task != nil {
+				return err
+			}
+
+			streams, err := client.XReadGroup(ctx, &redis.XReadGroupArgs{
+				Group:    group,
+				Consumer: q.consumer,
+				Streams:  []string{streamKey(), ">"},
+				Count:    1,
+				Block:    block,
+			}).Result()
+			if err == redis.Nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			for _, stream := range streams {
+				for _, message := range stream.Messages {
+					task = newTask(message, 1)
+				}
+			}
+			return nil
+		})
+		if err != nil || task == nil {
+			return nil, err
+		}
+
+		// Words whose leases kept expiring, e.g. because they crash the worker, are given up
+		if task.Attempt > q.options.MaxAttempts {
+			cause := fmt.Errorf("lease expired after %d attempts", task.Attempt-1)
+			if err := q.bury(ctx, client, *task, cause); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return task, nil
+	}
+}
+
+// reclaimPageSize is the number of pending entries reclaim inspects per round trip
+const reclaimPageSize = 100
+
+// reclaim claims a task whose lease has expired. It pages through all pending
+// entries, as expired ones may follow many tasks that are still being worked on.
+func (q *Queue) reclaim(ctx context.Context, client *redis.Client) (*Task, error) {
+	start := "-"
+	for {
+		pending, err := client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: streamKey(),
+			Group:  group,
+			Start:  start,
+			End:    "+",
+			Count:  reclaimPageSize,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range pending {
+			if entry.Idle < q.options.Visibility {
+				continue
+			}
+			// Claims only if no other worker claimed it first
+			messages, err := client.XClaim(ctx, &redis.XClaimArgs{
+				Stream:   streamKey(),
+				Group:    group,
+				Consumer: q.consumer,
+				MinIdle:  q.options.Visibility,
+				Messages: []string{entry.ID},
+			}).Result()
+			if err != nil {
+				return nil, err
+			}
+			if len(messages) > 0 {
+				return newTask(messages[0], entry.RetryCount+1), nil
+			}
+		}
+
+		if len(pending) < reclaimPageSize {
+			return nil, nil
+		}
+		start = nextStreamID(pending[len(pending)-1].ID)
+	}
+}
+
+// nextStreamID returns the smallest stream ID after id, for ranges that must
+// not include id itself
+func nextStreamID(id string) string {
+	ms, seq, found := strings.Cut(id, "-")
+	if !found {
+		return id
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return id
+	}
+	return ms + "-" + strconv.FormatUint(n+1, 10)
+}
+
+// newTask decodes a stream entry delivered the given number of times
+func newTask(message redis.XMessage, deliveries int64) *Task {
+	task := &Task{ID: message.ID}
+	task.Job, _ = message.Values["job"].(string)
+	task.Word, _ = message.Values["word"].(string)
+	attempt, _ := strconv.Atoi(fmt.Sprint(message.Values["attempt"]))
+	if attempt < 1 {
+		attempt = 1
+	}
+	task.Attempt = attempt + int(deliveries) - 1
+	return task
+}
+
+// extendScript renews a lease only while it is still held by the consumer
+var extendScript = redis.NewScript(`
+local pending = redis.call('XPENDING', KEYS[1], ARGV[1], ARGV[3], ARGV[3], 1, ARGV[2])
+if #pending == 0 then
+	return 0
+end
+redis.call('XCLAIM', KEYS[1], ARGV[1], ARGV[2], 0, ARGV[3], 'JUSTID')
+return 1
+`)
+
+// Extend renews the lease of a task. It returns ErrLeaseLost if the lease
+// expired and another worker claimed the task.
+func (q *Queue) Extend(task Task) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return err
+	}
+	return dbredis.Do(func() error {
+		held, err := extendScript.Run(ctx, client, []string{streamKey()}, group, q.consumer, task.ID).Int()
+		if err == nil && held == 0 {
+			err = ErrLeaseLost
+		}
+		return err
+	})
+}
+
+// expireFinishedJob is a Lua function that lets the progress of a job expire
+// after the given number of seconds once all its words are done or dead
+const expireFinishedJob = `
+local function expireFinishedJob(key, seconds)
+	local counts = redis.call('HMGET', key, 'total', 'done', 'dead')
+	local total = tonumber(counts[1]) or 0
+	if total > 0 and (tonumber(counts[2]) or 0) + (tonumber(counts[3]) or 0) >= total then
+		redis.call('EXPIRE', key, seconds)
+	end
+end
+`
+
+// completeScript acknowledges an entry and counts it as done once
+var completeScript = redis.NewScript(expireFinishedJob + `
+local acked = redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+if acked == 1 then
+	redis.call('HINCRBY', KEYS[2], 'done', 1)
+	expireFinishedJob(KEYS[2], ARGV[3])
+end
+return acked
+`)
+
+// retryScript acknowledges an entry and schedules its next attempt
+var retryScript = redis.NewScript(`
+local acked = redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+if acked == 1 then
+	redis.call('ZADD', KEYS[3], ARGV[3], ARGV[4])
+	redis.call('HINCRBY', KEYS[2], 'retried', 1)
+end
+return acked
+`)
+
+// buryScript acknowledges an entry and moves it to the dead letters
+var buryScript = redis.NewScript(expireFinishedJob + `
+local acked = redis.call('XACK', KEYS[1], ARGV[1], ARGV[2])
+redis.call('XDEL', KEYS[1], ARGV[2])
+if acked == 1 then
+	redis.call('XADD', KEYS[3], '*', 'job', ARGV[3], 'word', ARGV[4], 'attempts', ARGV[5], 'error', ARGV[6], 'failed_at', ARGV[7])
+	redis.call('HINCRBY', KEYS[2], 'dead', 1)
+	expireFinishedJob(KEYS[2], ARGV[8])
+end
+return acked
+`)
+
+// Complete removes a processed task from the queue
+func (q *Queue) Complete(task Task) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return err
+	}
+	return dbredis.Do(func() error {
+		return completeScript.Run(ctx, client, []string{streamKey(), jobKey(task.Job)},
+			group, task.ID, q.retentionSeconds()).Err()
+	})
+}
+
+// Fail records a failed attempt. The task is retried after a backoff, or
+// moved to the dead letters when it used up its attempts; dead reports which.
+func (q *Queue) Fail(task Task, cause error) (dead bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return false, err
+	}
+	if task.Attempt >= q.options.MaxAttempts {
+		return true, q.bury(ctx, client, task, cause)
+	}
+
+	retry, err := json.Marshal(map[string]interface{}{
+		"id":      task.ID, // keeps retries of equal words distinct
+		"job":     task.Job,
+		"word":    task.Word,
+		"attempt": task.Attempt + 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	due := time.Now().Add(q.backoff(task.Attempt))
+	return false, dbredis.Do(func() error {
+		return retryScript.Run(ctx, client, []string{streamKey(), jobKey(task.Job), delayedKey()},
+			group, task.ID, due.UnixMilli(), string(retry)).Err()
+	})
+}
+
+// bury moves a task to the dead letters
+func (q *Queue) bury(ctx context.Context, client *redis.Client, task Task, cause error) error {
+	return dbredis.Do(func() error {
+		return buryScript.Run(ctx, client, []string{streamKey(), jobKey(task.Job), deadKey()},
+			group, task.ID, task.Job, task.Word, task.Attempt, cause.Error(), time.Now().UTC().Format(time.RFC3339),
+			q.retentionSeconds()).Err()
+	})
+}
+
+// retentionSeconds returns the job retention in whole seconds, at least one
+func (q *Queue) retentionSeconds() int64 {
+	return max(int64(q.options.JobRetention/time.Second), 1)
+}
+
+// backoff returns the delay before the next attempt after the given number of failures
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.options.RetryBackoff
+	for i := 1; i < attempts && delay < q.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > q.options.MaxBackoff {
+		delay = q.options.MaxBackoff
+	}
+	return delay
+}
+
+// Stats counts the words in each state of the queue, over all jobs
+type Stats struct {
+	Queued   int64 `json:"queued"`    // waiting for a worker
+	InFlight int64 `json:"in_flight"` // leased to a worker
+	Delayed  int64 `json:"delayed"`   // waiting for a retry
+	Dead     int64 `json:"dead"`
+}
+
+// Idle reports whether no word is waiting, leased or scheduled for a retry
+func (s Stats) Idle() bool {
+	return s.Queued == 0 && s.InFlight == 0 && s.Delayed == 0
+}
+
+// Stats returns the current queue statistics
+func (q *Queue) Stats() (Stats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	var stats Stats
+	err = dbredis.Do(func() error {
+		pipe := client.Pipeline()
+		length := pipe.XLen(ctx, streamKey())
+		pending := pipe.XPending(ctx, streamKey(), group)
+		delayed := pipe.ZCard(ctx, delayedKey())
+		dead := pipe.XLen(ctx, deadKey())
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return err
+		}
+
+		stats.InFlight = pending.Val().Count
+		stats.Queued = length.Val() - stats.InFlight
+		stats.Delayed = delayed.Val()
+		stats.Dead = dead.Val()
+		return nil
+	})
+	return stats, err
+}
+
+// JobStatus is the progress of one job
+type JobStatus struct {
+	ID        string    `json:"id"`
+	Total     int       `json:"total"`
+	Done      int       `json:"done"`
+	Dead      int       `json:"dead"`
+	Retried   int       `json:"retried"` // failed attempts that were retried
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Remaining returns the number of words that are neither done nor dead
+func (j JobStatus) Remaining() int {
+	return j.Total - j.Done - j.Dead
+}
+
+// Jobs returns the status of all jobs, oldest first
+func (q *Queue) Jobs() ([]JobStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []JobStatus
+	err = dbredis.Do(func() error {
+		prefix := jobKey("")
+		iter := client.Scan(ctx, 0, strings.ReplaceAll(prefix, "*", `\*`)+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			fields, err := client.HGetAll(ctx, iter.Val()).Result()
+			if err != nil {
+				return err
+			}
+
+			job := JobStatus{ID: strings.TrimPrefix(iter.Val(), prefix)}
+			job.Total, _ = strconv.Atoi(fields["total"])
+			job.Done, _ = strconv.Atoi(fields["done"])
+			job.Dead, _ = strconv.Atoi(fields["dead"])
+			job.Retried, _ = strconv.Atoi(fields["retried"])
+			job.CreatedAt, _ = time.Parse(time.RFC3339, fields["created_at"])
+			jobs = append(jobs, job)
+		}
+		return iter.Err()
+	})
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs, err
+}
+
+// DeadTask is a word that failed too often
+type DeadTask struct {
+	Job      string    `json:"job"`
+	Word     string    `json:"word"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadTasks returns up to limit dead-lettered words, oldest first
+func (q *Queue) DeadTasks(limit int64) ([]DeadTask, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := q.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []redis.XMessage
+	err = dbredis.Do(func() error {
+		var err error
+		messages, err = client.XRangeN(ctx, deadKey(), "-", "+", limit).Result()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]DeadTask, 0, len(messages))
+	for _, message := range messages {
+		task := DeadTask{}
+		task.Job, _ = message.Values["job"].(string)
+		task.Word, _ = message.Values["word"].(string)
+		task.Error, _ = message.Values["error"].(string)
+		task.Attempts, _ = strconv.Atoi(fmt.Sprint(message.Values["attempts"]))
+		failedAt, _ := message.Values["failed_at"].(string)
+		task.FailedAt, _ = time.Parse(time.RFC3339, failedAt)
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
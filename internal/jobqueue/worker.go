@@ -0,0 +1,146 @@
+// File: internal/jobqueue/worker.go
+
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+)
+
+// Worker processes tasks of the queue concurrently
+type Worker struct {
+	queue       *Queue
+	process     func(task Task) error
+	concurrency int
+	block       time.Duration // longest wait for a task in one read
+
+	processed atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewWorker creates a Worker running process for every task with the given concurrency
+func NewWorker(queue *Queue, concurrency int, process func(task Task) error) *Worker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Worker{
+		queue:       queue,
+		process:     process,
+		concurrency: concurrency,
+		block:       5 * time.Second,
+	}
+}
+
+// Counts returns the number of tasks processed and of failed attempts
+func (w *Worker) Counts() (processed, failed int64) {
+	return w.processed.Load(), w.failed.Load()
+}
+
+// Run processes tasks until ctx is done, finishing the tasks in progress.
+// With untilEmpty it returns as soon as the queue is idle.
+func (w *Worker) Run(ctx context.Context, untilEmpty bool) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx, untilEmpty)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop leases and processes tasks one at a time
+func (w *Worker) loop(ctx context.Context, untilEmpty bool) {
+	for ctx.Err() == nil {
+		task, err := w.queue.Next(ctx, w.block)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warn("Failed to read from the work queue", logger.F("error", err))
+			sleep(ctx, w.block)
+			continue
+		}
+
+		if task == nil {
+			if untilEmpty {
+				if stats, err := w.queue.Stats(); err == nil && stats.Idle() {
+					return
+				}
+			}
+			continue
+		}
+
+		w.handle(*task)
+	}
+}
+
+// handle processes a task while renewing its lease and records the outcome
+func (w *Worker) handle(task Task) {
+	logger.Info("Processing queued word",
+		logger.F("word", task.Word), logger.F("job", task.Job), logger.F("attempt", task.Attempt))
+
+	done := make(chan struct{})
+	go w.renew(task, done)
+	err := w.process(task)
+	close(done)
+
+	if err == nil {
+		w.processed.Add(1)
+		if err := w.queue.Complete(task); err != nil {
+			logger.Error("Failed to complete queued word", logger.F("word", task.Word), logger.F("error", err))
+		}
+		return
+	}
+
+	w.failed.Add(1)
+	dead, failErr := w.queue.Fail(task, err)
+	switch {
+	case failErr != nil:
+		logger.Error("Failed to record failure of queued word", logger.F("word", task.Word), logger.F("error", failErr))
+	case dead:
+		logger.Error("Queued word moved to dead letters",
+			logger.F("word", task.Word), logger.F("attempts", task.Attempt), logger.F("error", err))
+	default:
+		logger.Warn("Failed to process queued word, will retry",
+			logger.F("word", task.Word), logger.F("attempt", task.Attempt), logger.F("error", err))
+	}
+}
+
+// renew extends the lease of a task until done is closed
+func (w *Worker) renew(task Task, done <-chan struct{}) {
+	interval := w.queue.options.Visibility / 3
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := w.queue.Extend(task); err != nil {
+				logger.Warn("Failed to renew lease of queued word", logger.F("word", task.Word), logger.F("error", err))
+				if errors.Is(err, ErrLeaseLost) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
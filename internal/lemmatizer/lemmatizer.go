@@ -0,0 +1,125 @@
+// File: internal/lemmatizer/lemmatizer.go
+
+package lemmatizer
+
+import (
+	"sync"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// Lookup methods recorded on resolved words
+const (
+	MethodExact      = "exact"
+	MethodInflection = "inflection"
+	MethodIrregular  = "irregular"
+	MethodRule       = "rule"
+	MethodSearch     = "search"
+)
+
+// Candidate is a possible lemma for an input form
+type Candidate struct {
+	Lemma  string
+	Method string
+}
+
+// Lemmatizer maps inflected forms such as "Häuser", "ging" or "schönsten" to
+// their lemmas. Known forms come from the declension and conjugation tables of
+// crawled entries; unknown forms fall back to German suffix stripping.
+type Lemmatizer struct {
+	forms map[string][]string // lookup key of a form -> lemmas
+	mutex sync.RWMutex
+}
+
+// NewLemmatizer creates an empty Lemmatizer
+func NewLemmatizer() *Lemmatizer {
+	return &Lemmatizer{
+		forms: make(map[string][]string),
+	}
+}
+
+// AddWord registers the lemma and all inflected forms of a crawled word
+func (l *Lemmatizer) AddWord(word *models.Word) {
+	if word == nil || word.Word == "" {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.addForm(word.Word, word.Word)
+	for _, form := range word.InflectedForms {
+		l.addForm(form, word.Word)
+	}
+}
+
+// addForm maps a form to a lemma; the caller must hold the write lock
+func (l *Lemmatizer) addForm(form, lemma string) {
+	key := models.LookupKey(form)
+	for _, known := range l.forms[key] {
+		if known == lemma {
+			return
+		}
+	}
+	l.forms[key] = append(l.forms[key], lemma)
+}
+
+// Load registers every word produced by forEach, e.g. a repository iterator
+func (l *Lemmatizer) Load(forEach func(fn func(word *models.Word) error) error) error {
+	return forEach(func(word *models.Word) error {
+		l.AddWord(word)
+		return nil
+	})
+}
+
+// Size returns the number of known forms
+func (l *Lemmatizer) Size() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return len(l.forms)
+}
+
+// Known returns the lemmas of a form found in crawled declension and conjugation data
+func (l *Lemmatizer) Known(form string) []string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	lemmas := l.forms[models.LookupKey(form)]
+	return append([]string(nil), lemmas...)
+}
+
+// Candidates returns possible lemmas for a form, most reliable first: lemmas
+// from crawled data, common irregular forms, the form itself and finally the
+// results of suffix stripping. Candidates are unique.
+func (l *Lemmatizer) Candidates(form string) []Candidate {
+	var candidates []Candidate
+	seen := make(map[string]bool)
+
+	add := func(lemma, method string) {
+		if lemma == "" || seen[lemma] {
+			return
+		}
+		seen[lemma] = true
+		candidates = append(candidates, Candidate{Lemma: lemma, Method: method})
+	}
+
+	for _, lemma := range l.Known(form) {
+		method := MethodInflection
+		if models.LookupKey(lemma) == models.LookupKey(form) {
+			method = MethodExact
+		}
+		add(lemma, method)
+	}
+
+	if lemma, ok := irregularForms[models.LookupKey(form)]; ok {
+		add(lemma, MethodIrregular)
+	}
+
+	add(form, MethodExact)
+
+	for _, lemma := range StripSuffixes(form) {
+		add(lemma, MethodRule)
+	}
+
+	return candidates
+}
@@ -0,0 +1,159 @@
+// File: internal/lemmatizer/rules.go
+
+package lemmatizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minStemLength keeps suffix stripping from reducing words to fragments
+const minStemLength = 3
+
+// suffixRule replaces an inflectional ending with the ending of the lemma
+type suffixRule struct {
+	suffix      string
+	replacement string
+	umlaut      bool // also try the stem without umlaut ("Häuser" -> "Haus")
+}
+
+// nounRules strip plural and case endings from capitalized forms: "Häusern", "Kindes", "Autos"
+var nounRules = []suffixRule{
+	{suffix: "ern", replacement: "", umlaut: true},
+	{suffix: "er", replacement: "", umlaut: true},
+	{suffix: "en", replacement: ""},
+	{suffix: "es", replacement: ""},
+	{suffix: "e", replacement: "", umlaut: true},
+	{suffix: "n", replacement: ""},
+	{suffix: "s", replacement: ""},
+}
+
+// wordRules strip comparison, verb and adjective endings from lowercase forms.
+// They are ordered longest first so that the most specific ending wins.
+var wordRules = []suffixRule{
+	// Superlatives and comparatives: "schönsten", "älteren"
+	{suffix: "esten", replacement: ""},
+	{suffix: "sten", replacement: "", umlaut: true},
+	{suffix: "eren", replacement: "", umlaut: true},
+	{suffix: "ere", replacement: "", umlaut: true},
+	{suffix: "ste", replacement: "", umlaut: true},
+	// Verb forms: "machtest", "machten", "gehst", "gehe"
+	{suffix: "test", replacement: "en"},
+	{suffix: "ten", replacement: "en"},
+	{suffix: "te", replacement: "en"},
+	{suffix: "est", replacement: "en"},
+	{suffix: "st", replacement: "en"},
+	{suffix: "et", replacement: "en"},
+	{suffix: "t", replacement: "en"},
+	{suffix: "e", replacement: "en"},
+	// Adjective endings: "schönen", "schönem", "großer", "größten"
+	{suffix: "en", replacement: ""},
+	{suffix: "em", replacement: ""},
+	{suffix: "es", replacement: ""},
+	{suffix: "er", replacement: ""},
+	{suffix: "e", replacement: ""},
+	{suffix: "ten", replacement: "", umlaut: true},
+}
+
+// irregularForms maps frequent strong and irregular verb forms to their infinitive.
+// Forms of crawled verbs are learned from their conjugation tables instead.
+var irregularForms = map[string]string{
+	"bin": "sein", "bist": "sein", "ist": "sein", "sind": "sein", "seid": "sein",
+	"war": "sein", "warst": "sein", "waren": "sein", "wart": "sein", "gewesen": "sein",
+	"hast": "haben", "hat": "haben", "hatte": "haben", "hatten": "haben", "gehabt": "haben",
+	"wirst": "werden", "wird": "werden", "wurde": "werden", "wurden": "werden", "geworden": "werden",
+	"ging": "gehen", "gingen": "gehen", "gegangen": "gehen",
+	"kam": "kommen", "kamen": "kommen", "gekommen": "kommen",
+	"sah": "sehen", "sahen": "sehen", "siehst": "sehen", "sieht": "sehen", "gesehen": "sehen",
+	"gab": "geben", "gaben": "geben", "gibst": "geben", "gibt": "geben", "gegeben": "geben",
+	"nahm": "nehmen", "nahmen": "nehmen", "nimmst": "nehmen", "nimmt": "nehmen", "genommen": "nehmen",
+	"stand": "stehen", "standen": "stehen", "gestanden": "stehen",
+	"fand": "finden", "fanden": "finden", "gefunden": "finden",
+	"sprach": "sprechen", "sprachen": "sprechen", "sprichst": "sprechen", "spricht": "sprechen", "gesprochen": "sprechen",
+	"lief": "laufen", "liefen": "laufen", "läufst": "laufen", "läuft": "laufen", "gelaufen": "laufen",
+	"fuhr": "fahren", "fuhren": "fahren", "fährst": "fahren", "fährt": "fahren", "gefahren": "fahren",
+	"trank": "trinken", "tranken": "trinken", "getrunken": "trinken",
+	"aß": "essen", "aßen": "essen", "isst": "essen", "gegessen": "essen",
+	"schrieb": "schreiben", "schrieben": "schreiben", "geschrieben": "schreiben",
+	"blieb": "bleiben", "blieben": "bleiben", "geblieben": "bleiben",
+	"dachte": "denken", "dachten": "denken", "gedacht": "denken",
+	"brachte": "bringen", "brachten": "bringen", "gebracht": "bringen",
+	"wusste": "wissen", "wussten": "wissen", "weiß": "wissen", "weißt": "wissen", "gewusst": "wissen",
+	"kann": "können", "kannst": "können", "konnte": "können", "konnten": "können",
+	"muss": "müssen", "musst": "müssen", "musste": "müssen", "mussten": "müssen",
+	"will": "wollen", "willst": "wollen", "wollte": "wollen", "wollten": "wollen",
+	"darf": "dürfen", "darfst": "dürfen", "durfte": "dürfen", "durften": "dürfen",
+	"mag": "mögen", "magst": "mögen", "mochte": "mögen", "mochten": "mögen",
+	"tat": "tun", "taten": "tun", "getan": "tun",
+}
+
+// StripSuffixes returns lemma guesses for a form by removing German inflectional
+// endings, most likely first. Capitalized forms are treated as nouns. Participles
+// lose their "ge-" prefix first ("gemacht" -> "machen").
+func StripSuffixes(form string) []string {
+	var lemmas []string
+	seen := map[string]bool{form: true}
+
+	add := func(lemma string) {
+		if !seen[lemma] {
+			seen[lemma] = true
+			lemmas = append(lemmas, lemma)
+		}
+	}
+
+	rules := wordRules
+	stems := []string{form}
+	if first, _ := utf8.DecodeRuneInString(form); unicode.IsUpper(first) {
+		rules = nounRules
+	} else if participle := strings.TrimPrefix(form, "ge"); participle != form && utf8.RuneCountInString(participle) > minStemLength {
+		stems = []string{participle, form}
+	}
+
+	for _, stem := range stems {
+		for _, rule := range rules {
+			if !strings.HasSuffix(stem, rule.suffix) {
+				continue
+			}
+			base := strings.TrimSuffix(stem, rule.suffix)
+			if utf8.RuneCountInString(base) < minStemLength {
+				continue
+			}
+
+			add(base + rule.replacement)
+			if rule.umlaut {
+				if plain := removeUmlaut(base); plain != base {
+					add(plain + rule.replacement)
+				}
+			}
+		}
+	}
+
+	return lemmas
+}
+
+// removeUmlaut undoes the plural or comparative umlaut on the last vowel group:
+// "Häus" -> "Haus", "größ" -> "groß", "Bäum" -> "Baum"
+func removeUmlaut(stem string) string {
+	runes := []rune(stem)
+	for i := len(runes) - 1; i >= 0; i-- {
+		switch runes[i] {
+		case 'ä':
+			runes[i] = 'a'
+		case 'ö':
+			runes[i] = 'o'
+		case 'ü':
+			runes[i] = 'u'
+		case 'Ä':
+			runes[i] = 'A'
+		case 'Ö':
+			runes[i] = 'O'
+		case 'Ü':
+			runes[i] = 'U'
+		default:
+			continue
+		}
+		return string(runes)
+	}
+	return stem
+}
@@ -0,0 +1,203 @@
+// File: internal/media/pipeline.go
+
+package media
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
+)
+
+// maxAssetSize guards against unexpectedly large downloads
+const maxAssetSize = 20 << 20
+
+// extensions for content types whose MIME registration varies between systems
+var extensions = map[string]string{
+	"audio/mpeg":    ".mp3",
+	"audio/mp3":     ".mp3",
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/gif":     ".gif",
+	"image/webp":    ".webp",
+	"image/svg+xml": ".svg",
+}
+
+// Pipeline downloads pronunciation audio and meaning images of a word into a
+// Store and records the stored files on the word
+type Pipeline struct {
+	store     *Store
+	client    *http.Client
+	limiter   func() utils.Limiter
+	baseURL   string
+	userAgent string
+}
+
+// NewPipeline creates a Pipeline. limiter returns the limiter the scraper
+// uses, so that media downloads count against the same request budget. It is
+// asked on every download, so that a limiter installed later, e.g. one shared
+// with other processes, applies as well.
+func NewPipeline(store *Store, limiter func() utils.Limiter) *Pipeline {
+	return &Pipeline{
+		store: store,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		limiter:   limiter,
+		baseURL:   "https://www.duden.de",
+		userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+	}
+}
+
+// Store returns the pipeline's media store
+func (p *Pipeline) Store() *Store {
+	return p.store
+}
+
+// Process downloads all media of a word that is not stored yet.
+// It reports whether any file reference on the word changed. Failed downloads
+// are logged and skipped so that one broken asset does not block the word.
+func (p *Pipeline) Process(word *models.Word) bool {
+	changed := false
+
+	for i := range word.Pronunciation {
+		pronunciation := &word.Pronunciation[i]
+		if pronunciation.Audio == "" {
+			continue
+		}
+		if file := p.fetch(pronunciation.Audio); file != nil && !sameFile(pronunciation.AudioFile, file) {
+			pronunciation.AudioFile = file
+			changed = true
+		}
+	}
+
+	for i := range word.Meanings {
+		if p.processMeaning(&word.Meanings[i]) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// processMeaning downloads the image of a meaning and its sub-meanings
+func (p *Pipeline) processMeaning(meaning *models.Meaning) bool {
+	changed := false
+
+	if meaning.Image != "" {
+		if file := p.fetch(meaning.Image); file != nil && !sameFile(meaning.ImageFile, file) {
+			meaning.ImageFile = file
+			changed = true
+		}
+	}
+
+	for i := range meaning.SubMeanings {
+		if p.processMeaning(&meaning.SubMeanings[i]) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// fetch returns the stored file for a URL, downloading it on first use
+func (p *Pipeline) fetch(link string) *models.MediaFile {
+	url := p.absoluteURL(link)
+
+	if file, exists := p.store.Lookup(url); exists {
+		return file
+	}
+
+	file, err := p.download(url)
+	if err != nil {
+		logger.Warn("Failed to download media", logger.F("url", url), logger.F("error", err))
+		return nil
+	}
+
+	logger.Info("Downloaded media", logger.F("url", url), logger.F("path", file.Path), logger.F("size", file.Size))
+	return file
+}
+
+// download fetches a URL through the rate limiter and puts it into the store
+func (p *Pipeline) download(url string) (*models.MediaFile, error) {
+	p.limiter().Wait()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxAssetSize {
+		return nil, fmt.Errorf("asset too large: %d bytes", resp.ContentLength)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	body := &sizeLimitReader{reader: resp.Body, remaining: maxAssetSize}
+
+	return p.store.Put(url, body, extension(url, contentType), contentType)
+}
+
+// sizeLimitReader fails once more than the allowed number of bytes has been read,
+// so oversized assets never make it into the store
+type sizeLimitReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+// Read implements io.Reader
+func (r *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, fmt.Errorf("asset too large: more than %d bytes", maxAssetSize)
+	}
+	return n, err
+}
+
+// absoluteURL resolves links such as "/_media_/full/H/Haus.jpg" against the Duden base URL
+func (p *Pipeline) absoluteURL(link string) string {
+	if strings.HasPrefix(link, "//") {
+		return "https:" + link
+	}
+	if strings.HasPrefix(link, "/") {
+		return p.baseURL + link
+	}
+	return link
+}
+
+// extension picks a file extension from the content type, falling back to the URL
+func extension(url, contentType string) string {
+	if ext, exists := extensions[contentType]; exists {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+
+	if idx := strings.IndexAny(url, "?#"); idx >= 0 {
+		url = url[:idx]
+	}
+	return strings.ToLower(path.Ext(url))
+}
+
+// sameFile reports whether a stored reference already points at file
+func sameFile(current, file *models.MediaFile) bool {
+	return current != nil && current.Checksum == file.Checksum && current.Path == file.Path
+}
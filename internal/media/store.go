@@ -0,0 +1,133 @@
+// File: internal/media/store.go
+
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// indexFile maps source URLs to stored files so assets are not downloaded twice
+const indexFile = "index.json"
+
+// Store is a content-addressed file store. Files are named after the SHA-256
+// of their content ("ab/ab12….mp3"), so identical assets are stored once.
+type Store struct {
+	root  string
+	index map[string]models.MediaFile // source URL -> stored file
+	mutex sync.Mutex
+}
+
+// NewStore opens the store at root, creating the directory if needed
+func NewStore(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+
+	s := &Store{
+		root:  root,
+		index: make(map[string]models.MediaFile),
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, indexFile))
+	if err == nil {
+		if err := json.Unmarshal(data, &s.index); err != nil {
+			return nil, fmt.Errorf("failed to read media index: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Root returns the store's root directory
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Lookup returns the stored file for a source URL if it was downloaded before
+// and is still present on disk
+func (s *Store) Lookup(url string) (*models.MediaFile, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, exists := s.index[url]
+	if !exists {
+		return nil, false
+	}
+	if _, err := os.Stat(s.AbsPath(file)); err != nil {
+		delete(s.index, url)
+		return nil, false
+	}
+	return &file, true
+}
+
+// Put stores content read from r under its checksum and records it for url.
+// ext is the file extension including the dot, e.g. ".mp3".
+func (s *Store) Put(url string, r io.Reader, ext, contentType string) (*models.MediaFile, error) {
+	tmp, err := os.CreateTemp(s.root, "download-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	file := models.MediaFile{
+		Checksum:    checksum,
+		Size:        size,
+		Path:        filepath.ToSlash(filepath.Join(checksum[:2], checksum+ext)),
+		ContentType: contentType,
+	}
+
+	target := s.AbsPath(file)
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmp.Name(), target); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.index[url] = file
+	return &file, s.saveIndex()
+}
+
+// AbsPath returns the location of a stored file on disk
+func (s *Store) AbsPath(file models.MediaFile) string {
+	return filepath.Join(s.root, filepath.FromSlash(file.Path))
+}
+
+// saveIndex writes the URL index; the caller must hold the lock
+func (s *Store) saveIndex() error {
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(s.root, indexFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.root, indexFile))
+}
@@ -0,0 +1,308 @@
+// File: internal/outbox/dispatcher.go
+
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// Backend is a store words are replicated to
+type Backend struct {
+	Name  string
+	Apply func(word *models.Word) error
+}
+
+// Options configures a Dispatcher
+type Options struct {
+	Workers      int           // entries applied concurrently
+	MaxAttempts  int           // failed attempts before an entry is dead-lettered, unless its backend is unavailable
+	Lease        time.Duration // time a worker may take to apply an entry before others may claim it
+	PollInterval time.Duration // interval between checks for due entries
+	RetryBackoff time.Duration // delay before the first retry, doubled on every further attempt
+	MaxBackoff   time.Duration
+}
+
+// DefaultOptions returns the default dispatcher options
+func DefaultOptions() Options {
+	return Options{
+		Workers:      4,
+		MaxAttempts:  8,
+		Lease:        2 * time.Minute,
+		PollInterval: 5 * time.Second,
+		RetryBackoff: 5 * time.Second,
+		MaxBackoff:   10 * time.Minute,
+	}
+}
+
+// Dispatcher records saves of words in a Store and applies them to the
+// backends. The first backend is the primary store: it is written while
+// saving, the others by background workers.
+type Dispatcher struct {
+	store    Store
+	backends []Backend
+	options  Options
+
+	startOnce sync.Once
+	started   atomic.Bool
+	wake      chan struct{}
+
+	mutex   sync.Mutex
+	waiters []chan struct{} // Drain calls waiting for the outbox to become idle
+}
+
+// NewDispatcher creates a Dispatcher for backends, primary store first
+func NewDispatcher(store Store, backends []Backend, options Options) *Dispatcher {
+	defaults := DefaultOptions()
+	if options.Workers <= 0 {
+		options.Workers = defaults.Workers
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaults.MaxAttempts
+	}
+	if options.Lease <= 0 {
+		options.Lease = defaults.Lease
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaults.PollInterval
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaults.RetryBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return &Dispatcher{
+		store:    store,
+		backends: backends,
+		options:  options,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Store returns the store of the outbox
+func (d *Dispatcher) Store() Store {
+	return d.store
+}
+
+// Save records a word for every backend and writes it to the primary store.
+// Writes to the other backends are applied in the background. The returned
+// error is that of the primary write, whose entry then stays in the outbox
+// for retry; an error wrapping ErrEnqueue means nothing was recorded.
+//
+// The primary write takes the lease of its entry first, so a worker still
+// applying an earlier version of the word finishes before it and cannot
+// overwrite it.
+func (d *Dispatcher) Save(word *models.Word) error {
+	now := time.Now()
+	entries := make([]Entry, 0, len(d.backends))
+	for _, backend := range d.backends {
+		entry := Entry{
+			ID:          entryID(backend.Name, word.ID),
+			Backend:     backend.Name,
+			WordID:      word.ID,
+			Word:        word,
+			Version:     now.UnixNano(),
+			Status:      StatusPending,
+			NextAttempt: now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := d.store.Enqueue(entries); err != nil {
+		return fmt.Errorf("%w: %v", ErrEnqueue, err)
+	}
+
+	d.Start()
+	defer d.notify()
+	if err := d.acquire(entries[0]); err != nil {
+		if errors.Is(err, ErrSuperseded) {
+			// A worker applied this version, or a newer save replaced it
+			return nil
+		}
+		return err
+	}
+	return d.apply(entries[0])
+}
+
+// acquirePollInterval is the interval at which Save checks whether a worker
+// released the lease of the primary entry
+const acquirePollInterval = 50 * time.Millisecond
+
+// acquire waits until it holds the lease of entry. A lease held by a worker
+// ends when the worker is done or the lease expires.
+func (d *Dispatcher) acquire(entry Entry) error {
+	for {
+		acquired, err := d.store.Acquire(entry, time.Now(), d.options.Lease)
+		if err != nil || acquired {
+			return err
+		}
+		time.Sleep(acquirePollInterval)
+	}
+}
+
+// ErrEnqueue reports that a save could not be recorded in the outbox
+var ErrEnqueue = errors.New("failed to record write in outbox")
+
+// Start starts the background workers. It is called by Save and may be called
+// again safely.
+func (d *Dispatcher) Start() {
+	d.startOnce.Do(func() {
+		d.started.Store(true)
+		go d.run()
+	})
+}
+
+// Active reports whether the workers were started, i.e. whether this process
+// saved words or processes the outbox
+func (d *Dispatcher) Active() bool {
+	return d.started.Load()
+}
+
+// Drain waits until no due entries are left or timeout expires and reports
+// whether the outbox became idle. Entries waiting for a retry are not awaited.
+func (d *Dispatcher) Drain(timeout time.Duration) bool {
+	d.Start()
+
+	idle := make(chan struct{})
+	d.mutex.Lock()
+	d.waiters = append(d.waiters, idle)
+	d.mutex.Unlock()
+	d.notify()
+
+	select {
+	case <-idle:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// notify wakes the workers
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run claims and applies due entries until the process exits
+func (d *Dispatcher) run() {
+	for {
+		// Drain calls made before this pass are answered if it finds nothing to do
+		d.mutex.Lock()
+		waiters := d.waiters
+		d.waiters = nil
+		d.mutex.Unlock()
+
+		applied, err := d.ProcessDue()
+		if err != nil {
+			logger.Error("Failed to claim outbox entries", logger.F("error", err))
+		}
+		if applied > 0 {
+			d.mutex.Lock()
+			d.waiters = append(waiters, d.waiters...)
+			d.mutex.Unlock()
+			continue
+		}
+
+		for _, waiter := range waiters {
+			close(waiter)
+		}
+		select {
+		case <-d.wake:
+		case <-time.After(d.options.PollInterval):
+		}
+	}
+}
+
+// ProcessDue claims the entries that are due and applies them with the
+// configured number of workers. It returns the number of entries processed.
+func (d *Dispatcher) ProcessDue() (int, error) {
+	entries, err := d.store.Claim(time.Now(), d.options.Lease, d.options.Workers*4)
+	if len(entries) == 0 {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, d.options.Workers)
+	for _, entry := range entries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(entry Entry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			_ = d.apply(entry)
+		}(entry)
+	}
+	wg.Wait()
+	return len(entries), err
+}
+
+// apply writes an entry to its backend and records the outcome
+func (d *Dispatcher) apply(entry Entry) error {
+	var backend *Backend
+	for i := range d.backends {
+		if d.backends[i].Name == entry.Backend {
+			backend = &d.backends[i]
+		}
+	}
+
+	var err error
+	if backend == nil {
+		err = fmt.Errorf("unknown backend %q", entry.Backend)
+	} else if entry.Word == nil {
+		err = fmt.Errorf("entry has no word")
+	} else {
+		err = backend.Apply(entry.Word)
+	}
+
+	if err == nil {
+		logger.Debug("Applied outbox entry", logger.F("backend", entry.Backend), logger.F("word", entry.WordID))
+		if storeErr := d.store.Complete(entry); storeErr != nil {
+			logger.Error("Failed to complete outbox entry", logger.F("entry", entry.ID), logger.F("error", storeErr))
+		}
+		return nil
+	}
+
+	// Entries for an unavailable backend wait for it instead of being dead-lettered
+	attempts := entry.Attempts + 1
+	dead := (attempts >= d.options.MaxAttempts && !errors.Is(err, health.ErrUnavailable)) ||
+		backend == nil || entry.Word == nil
+	next := time.Now().Add(d.backoff(attempts))
+	if dead {
+		logger.Error("Outbox entry moved to dead letters",
+			logger.F("backend", entry.Backend), logger.F("word", entry.WordID),
+			logger.F("attempts", attempts), logger.F("error", err))
+	} else {
+		logger.Warn("Failed to apply outbox entry, will retry",
+			logger.F("backend", entry.Backend), logger.F("word", entry.WordID),
+			logger.F("attempts", attempts), logger.F("retry_at", next.Format(time.RFC3339)), logger.F("error", err))
+	}
+	if storeErr := d.store.Fail(entry, err, next, dead); storeErr != nil {
+		logger.Error("Failed to record outbox failure", logger.F("entry", entry.ID), logger.F("error", storeErr))
+	}
+	return err
+}
+
+// backoff returns the delay before the next attempt after the given number of failures
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.options.RetryBackoff
+	for i := 1; i < attempts && delay < d.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.options.MaxBackoff {
+		delay = d.options.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,294 @@
+// File: internal/outbox/file_store.go
+
+package outbox
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileStore keeps the outbox as one JSON file per entry in a local directory.
+// It works while every database is down, but is meant for a single process:
+// concurrent processes may apply an entry twice, which is harmless as all
+// writes are upserts.
+type FileStore struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewFileStore creates a FileStore in dir. The directory is created on first use.
+func NewFileStore(dir string) *FileStore {
+	if dir == "" {
+		dir = "outbox"
+	}
+	return &FileStore{dir: dir}
+}
+
+// path returns the file of an entry; IDs are hashed as they contain arbitrary slugs
+func (f *FileStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// read loads an entry, returning nil if it does not exist
+func (f *FileStore) read(id string) (*Entry, error) {
+	data, err := os.ReadFile(f.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// write stores an entry atomically
+func (f *FileStore) write(entry Entry) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(f.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(entry.ID))
+}
+
+// all loads every entry
+func (f *FileStore) all() ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(f.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Completed by another process
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Enqueue implements Store
+func (f *FileStore) Enqueue(entries []Entry) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, entry := range entries {
+		existing, err := f.read(entry.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			entry.LeaseUntil = existing.LeaseUntil
+			entry.CreatedAt = existing.CreatedAt
+		}
+		entry.Status = StatusPending
+		entry.Attempts = 0
+		entry.LastError = ""
+		if err := f.write(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Claim implements Store
+func (f *FileStore) Claim(now time.Time, lease time.Duration, limit int) ([]Entry, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entries, err := f.all()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].NextAttempt.Before(entries[j].NextAttempt)
+	})
+
+	var claimed []Entry
+	for _, entry := range entries {
+		if len(claimed) >= limit {
+			break
+		}
+		if entry.Status != StatusPending || entry.NextAttempt.After(now) || entry.LeaseUntil.After(now) {
+			continue
+		}
+		entry.LeaseUntil = now.Add(lease)
+		if err := f.write(entry); err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, entry)
+	}
+	return claimed, nil
+}
+
+// Acquire implements Store
+func (f *FileStore) Acquire(entry Entry, now time.Time, lease time.Duration) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	current, err := f.read(entry.ID)
+	if err != nil {
+		return false, err
+	}
+	if current == nil || current.Version != entry.Version || current.Status != StatusPending {
+		return false, ErrSuperseded
+	}
+	if current.LeaseUntil.After(now) {
+		return false, nil
+	}
+	current.LeaseUntil = now.Add(lease)
+	return true, f.write(*current)
+}
+
+// Complete implements Store
+func (f *FileStore) Complete(entry Entry) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	current, err := f.read(entry.ID)
+	if err != nil || current == nil {
+		return err
+	}
+	if current.Version != entry.Version {
+		current.LeaseUntil = time.Time{}
+		return f.write(*current)
+	}
+	err = os.Remove(f.path(entry.ID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Fail implements Store
+func (f *FileStore) Fail(entry Entry, cause error, next time.Time, dead bool) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	current, err := f.read(entry.ID)
+	if err != nil || current == nil {
+		return err
+	}
+	current.LeaseUntil = time.Time{}
+	if current.Version == entry.Version {
+		current.Attempts++
+		current.LastError = cause.Error()
+		current.NextAttempt = next
+		current.UpdatedAt = time.Now()
+		if dead {
+			current.Status = StatusDead
+		}
+	}
+	return f.write(*current)
+}
+
+// List implements Store
+func (f *FileStore) List(status Status, backend string, limit int) ([]Entry, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entries, err := f.all()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	var listed []Entry
+	for _, entry := range entries {
+		if entry.Status != status || (backend != "" && entry.Backend != backend) {
+			continue
+		}
+		listed = append(listed, entry)
+		if limit > 0 && len(listed) >= limit {
+			break
+		}
+	}
+	return listed, nil
+}
+
+// Stats implements Store
+func (f *FileStore) Stats() ([]BackendStats, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entries, err := f.all()
+	if err != nil {
+		return nil, err
+	}
+	return summarize(entries), nil
+}
+
+// Requeue implements Store
+func (f *FileStore) Requeue(backend string) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entries, err := f.all()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	requeued := 0
+	for _, entry := range entries {
+		if entry.Status != StatusDead || (backend != "" && entry.Backend != backend) {
+			continue
+		}
+		entry.Status = StatusPending
+		entry.Attempts = 0
+		entry.NextAttempt = now
+		entry.LeaseUntil = time.Time{}
+		entry.UpdatedAt = now
+		if err := f.write(entry); err != nil {
+			return requeued, err
+		}
+		requeued++
+	}
+	return requeued, nil
+}
@@ -0,0 +1,283 @@
+// File: internal/outbox/mongo_store.go
+
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStore keeps the outbox in the "outbox" collection of the primary
+// database. Entries are claimed atomically, so any number of processes can
+// share it.
+type MongoStore struct {
+	indexesOnce sync.Once
+}
+
+// NewMongoStore creates a MongoStore. The database is connected on first use.
+func NewMongoStore() *MongoStore {
+	return &MongoStore{}
+}
+
+// collection returns the outbox collection, creating its index on first use
+func (m *MongoStore) collection() (*mongo.Collection, error) {
+	collection, err := mongodb.GetCollection("outbox")
+	if err != nil {
+		return nil, err
+	}
+	m.indexesOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, _ = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt", Value: 1}},
+		})
+	})
+	return collection, nil
+}
+
+// Enqueue implements Store
+func (m *MongoStore) Enqueue(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	writes := make([]mongo.WriteModel, 0, len(entries))
+	for _, entry := range entries {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": entry.ID}).
+			SetUpsert(true).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"backend":      entry.Backend,
+					"word_id":      entry.WordID,
+					"word":         entry.Word,
+					"version":      entry.Version,
+					"status":       StatusPending,
+					"attempts":     0,
+					"last_error":   "",
+					"next_attempt": entry.NextAttempt,
+					"updated_at":   entry.UpdatedAt,
+				},
+				"$setOnInsert": bson.M{
+					"lease_until": entry.LeaseUntil,
+					"created_at":  entry.CreatedAt,
+				},
+			}))
+	}
+	collection, err := m.collection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// Claim implements Store
+func (m *MongoStore) Claim(now time.Time, lease time.Duration, limit int) ([]Entry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"status":       StatusPending,
+		"next_attempt": bson.M{"$lte": now},
+		"lease_until":  bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"lease_until": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	collection, err := m.collection()
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for len(entries) < limit {
+		var entry Entry
+		err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&entry)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Acquire implements Store
+func (m *MongoStore) Acquire(entry Entry, now time.Time, lease time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := m.collection()
+	if err != nil {
+		return false, err
+	}
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": entry.ID, "version": entry.Version, "status": StatusPending, "lease_until": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"lease_until": now.Add(lease)}})
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount > 0 {
+		return true, nil
+	}
+
+	// Not acquired: either another worker holds the lease or the version is gone
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": entry.ID, "version": entry.Version, "status": StatusPending})
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, ErrSuperseded
+	}
+	return false, nil
+}
+
+// Complete implements Store
+func (m *MongoStore) Complete(entry Entry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := m.collection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": entry.ID, "version": entry.Version})
+	if err != nil || result.DeletedCount > 0 {
+		return err
+	}
+	return m.release(ctx, entry)
+}
+
+// Fail implements Store
+func (m *MongoStore) Fail(entry Entry, cause error, next time.Time, dead bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status := StatusPending
+	if dead {
+		status = StatusDead
+	}
+	collection, err := m.collection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": entry.ID, "version": entry.Version},
+		bson.M{
+			"$set": bson.M{
+				"status":       status,
+				"last_error":   cause.Error(),
+				"next_attempt": next,
+				"lease_until":  time.Time{},
+				"updated_at":   time.Now(),
+			},
+			"$inc": bson.M{"attempts": 1},
+		})
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+	return m.release(ctx, entry)
+}
+
+// release clears the lease of a replaced entry
+func (m *MongoStore) release(ctx context.Context, entry Entry) error {
+	collection, err := m.collection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": entry.ID},
+		bson.M{"$set": bson.M{"lease_until": time.Time{}}})
+	return err
+}
+
+// List implements Store
+func (m *MongoStore) List(status Status, backend string, limit int) ([]Entry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{"status": status}
+	if backend != "" {
+		filter["backend"] = backend
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+	return m.find(ctx, filter, opts)
+}
+
+// Stats implements Store
+func (m *MongoStore) Stats() ([]BackendStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Words are not needed for counting
+	entries, err := m.find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"word": 0}))
+	if err != nil {
+		return nil, err
+	}
+	return summarize(entries), nil
+}
+
+// find decodes the entries matching filter
+func (m *MongoStore) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]Entry, error) {
+	collection, err := m.collection()
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []Entry
+	for cursor.Next(ctx) {
+		var entry Entry
+		if err := cursor.Decode(&entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, cursor.Err()
+}
+
+// Requeue implements Store
+func (m *MongoStore) Requeue(backend string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{"status": StatusDead}
+	if backend != "" {
+		filter["backend"] = backend
+	}
+	collection, err := m.collection()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	result, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"status":       StatusPending,
+		"attempts":     0,
+		"next_attempt": now,
+		"lease_until":  time.Time{},
+		"updated_at":   now,
+	}})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
@@ -0,0 +1,148 @@
+// File: internal/outbox/outbox.go
+
+// Package outbox queues writes to the word stores durably, so that a slow or
+// failing backend does not block or partially fail saving a word. Every save
+// is recorded as one entry per backend; a Dispatcher applies the entries with
+// retries and moves entries that keep failing to a dead-letter state.
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// Status is the state of an outbox entry
+type Status string
+
+// Entry states. Applied entries are removed from the outbox.
+const (
+	StatusPending Status = "pending"
+	StatusDead    Status = "dead"
+)
+
+// Store kinds accepted by NewStore
+const (
+	StoreMongoDB = "mongodb"
+	StoreFile    = "file"
+	StoreOff     = "off"
+)
+
+// Entry is a pending write of a word to one backend. There is at most one
+// entry per word and backend: saving a word again replaces the entry, so only
+// the latest version is applied.
+type Entry struct {
+	ID          string       `json:"id" bson:"_id"`
+	Backend     string       `json:"backend" bson:"backend"`
+	WordID      string       `json:"word_id" bson:"word_id"`
+	Word        *models.Word `json:"word" bson:"word"`
+	Version     int64        `json:"version" bson:"version"` // enqueue time in nanoseconds, distinguishes replaced entries
+	Status      Status       `json:"status" bson:"status"`
+	Attempts    int          `json:"attempts" bson:"attempts"`
+	LastError   string       `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttempt time.Time    `json:"next_attempt" bson:"next_attempt"`
+	LeaseUntil  time.Time    `json:"lease_until" bson:"lease_until"` // set while a worker applies the entry
+	CreatedAt   time.Time    `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+// ErrSuperseded reports that an entry no longer holds the version it was read with
+var ErrSuperseded = errors.New("outbox entry was superseded")
+
+// entryID returns the ID of the entry of a word in a backend
+func entryID(backend, wordID string) string {
+	return backend + ":" + wordID
+}
+
+// BackendStats summarizes the entries of one backend
+type BackendStats struct {
+	Backend       string    `json:"backend"`
+	Pending       int       `json:"pending"`
+	Retrying      int       `json:"retrying"` // pending entries that failed at least once
+	Dead          int       `json:"dead"`
+	OldestPending time.Time `json:"oldest_pending"`
+	NextAttempt   time.Time `json:"next_attempt"`
+}
+
+// Store persists outbox entries
+type Store interface {
+	// Enqueue inserts entries or replaces the entries with the same ID. A
+	// replaced entry keeps its lease, so that two versions of a word are
+	// never applied to a backend at the same time.
+	Enqueue(entries []Entry) error
+
+	// Claim leases up to limit pending entries that are due at now and not leased
+	Claim(now time.Time, lease time.Duration, limit int) ([]Entry, error)
+
+	// Acquire leases a pending entry regardless of when it is due. It returns
+	// false if another worker holds the lease, and ErrSuperseded if the entry
+	// was replaced, applied or marked dead in the meantime.
+	Acquire(entry Entry, now time.Time, lease time.Duration) (bool, error)
+
+	// Complete removes an applied entry. If the entry was replaced in the
+	// meantime, only its lease is released.
+	Complete(entry Entry) error
+
+	// Fail records a failed attempt and schedules the entry for retry at next,
+	// or marks it dead. If the entry was replaced in the meantime, only its
+	// lease is released.
+	Fail(entry Entry, cause error, next time.Time, dead bool) error
+
+	// List returns entries with the given status, optionally of one backend,
+	// oldest first. A limit of 0 returns all entries.
+	List(status Status, backend string, limit int) ([]Entry, error)
+
+	// Stats summarizes the entries per backend
+	Stats() ([]BackendStats, error)
+
+	// Requeue makes dead entries pending again, optionally of one backend
+	Requeue(backend string) (int, error)
+}
+
+// NewStore creates the store of the given kind: "mongodb" keeps the outbox in
+// a collection of the primary database, "file" in JSON files below dir. "off"
+// returns a nil store, disabling the outbox.
+func NewStore(kind, dir string) (Store, error) {
+	switch kind {
+	case StoreMongoDB, "":
+		return NewMongoStore(), nil
+	case StoreFile:
+		return NewFileStore(dir), nil
+	case StoreOff:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown outbox store %q (use %s, %s or %s)", kind, StoreMongoDB, StoreFile, StoreOff)
+}
+
+// summarize computes per-backend statistics from entries without their words
+func summarize(entries []Entry) []BackendStats {
+	var stats []BackendStats
+	index := make(map[string]int)
+	for _, entry := range entries {
+		i, exists := index[entry.Backend]
+		if !exists {
+			i = len(stats)
+			index[entry.Backend] = i
+			stats = append(stats, BackendStats{Backend: entry.Backend})
+		}
+
+		s := &stats[i]
+		if entry.Status == StatusDead {
+			s.Dead++
+			continue
+		}
+		s.Pending++
+		if entry.Attempts > 0 {
+			s.Retrying++
+		}
+		if s.OldestPending.IsZero() || entry.CreatedAt.Before(s.OldestPending) {
+			s.OldestPending = entry.CreatedAt
+		}
+		if s.NextAttempt.IsZero() || entry.NextAttempt.Before(s.NextAttempt) {
+			s.NextAttempt = entry.NextAttempt
+		}
+	}
+	return stats
+}
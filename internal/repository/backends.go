@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/elasticsearch"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/filter"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/mongodb"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/postgres"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// storeBackend pairs a backend iterator with the function that writes a word back
+type storeBackend struct {
+	name     string
+	forEach  func(fn func(word *models.Word) error) error
+	matching func(f filter.Words, fn func(word *models.Word) error) error // iterates the words selected by f
+	save     func(word *models.Word) error
+	check    func() health.Status
+	cache    bool // entries expire, so missing words are expected
+}
+
+// storeBackends are the stores words are kept in, primary storage first.
+// Redis writes keep the remaining TTL of cached words.
+var storeBackends = []storeBackend{
+	{name: "mongodb", forEach: mongodb.ForEachWord, matching: mongodb.ForEachWordMatching, save: mongodb.SaveWord, check: mongodb.CheckHealth},
+	{name: "postgres", forEach: postgres.ForEachWord, matching: postgres.ForEachWordMatching, save: postgres.SaveWord, check: postgres.CheckHealth},
+	{name: "redis", forEach: redis.ForEachCachedWord, matching: scanMatching(redis.ForEachCachedWord), save: redis.RecacheWord, check: redis.CheckHealth, cache: true},
+	{name: "elasticsearch", forEach: elasticsearch.ForEachWord, matching: elasticsearch.ForEachWordMatching, save: elasticsearch.IndexWord, check: elasticsearch.CheckHealth},
+}
+
+// scanMatching filters the words of a store that cannot query their fields,
+// such as Redis, while scanning all of them
+func scanMatching(forEach func(fn func(word *models.Word) error) error) func(f filter.Words, fn func(word *models.Word) error) error {
+	return func(f filter.Words, fn func(word *models.Word) error) error {
+		return forEach(func(word *models.Word) error {
+			if !f.Matches(word) {
+				return nil
+			}
+			return fn(word)
+		})
+	}
+}
+
+// BackendNames returns the names of the stores words can be read from
+func BackendNames() []string {
+	names := make([]string, 0, len(storeBackends))
+	for _, backend := range storeBackends {
+		names = append(names, backend.name)
+	}
+	return names
+}
+
+// WordFilter selects stored words. Empty fields match every word.
+type WordFilter = filter.Words
+
+// ForEachWordIn streams every word stored in the named backend that matches
+// filter through fn. MongoDB, PostgreSQL and Elasticsearch select the words
+// in their query and return them through a cursor or scroll; Redis scans all
+// cached words.
+func (r *WordRepository) ForEachWordIn(backend string, filter WordFilter, fn func(word *models.Word) error) error {
+	for _, b := range storeBackends {
+		if b.name == backend {
+			return b.matching(filter, fn)
+		}
+	}
+	return fmt.Errorf("unknown backend %q (available: %s)", backend, strings.Join(BackendNames(), ", "))
+}
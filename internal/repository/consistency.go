@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// BackendConsistency summarizes one backend in a ConsistencyReport
+type BackendConsistency struct {
+	Backend   string `json:"backend"`
+	Documents int    `json:"documents"`
+	Missing   int    `json:"missing"`
+	Divergent int    `json:"divergent"`
+	Repaired  int    `json:"repaired"`
+	Cache     bool   `json:"cache,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ConsistencyIssue describes an entry whose copies differ between backends.
+// Hashes maps each backend holding the entry to the content hash of its copy.
+type ConsistencyIssue struct {
+	ID        string            `json:"id"`
+	Word      string            `json:"word"`
+	Hashes    map[string]string `json:"hashes"`
+	Missing   []string          `json:"missing,omitempty"`
+	Divergent []string          `json:"divergent,omitempty"`
+	Orphaned  bool              `json:"orphaned,omitempty"` // not in the source backend, so it cannot be repaired
+	Repaired  []string          `json:"repaired,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+// ConsistencyReport is the result of VerifyConsistency
+type ConsistencyReport struct {
+	Source   string               `json:"source"`
+	Repair   bool                 `json:"repair"`
+	DryRun   bool                 `json:"dry_run,omitempty"`
+	Entries  int                  `json:"entries"`
+	Backends []BackendConsistency `json:"backends"`
+	Issues   []ConsistencyIssue   `json:"issues"`
+}
+
+// Consistent reports whether no issues are left and every backend could be read.
+// Repairs of a dry run do not count.
+func (r *ConsistencyReport) Consistent() bool {
+	if r.DryRun && len(r.Issues) > 0 {
+		return false
+	}
+	for _, backend := range r.Backends {
+		if backend.Error != "" {
+			return false
+		}
+	}
+	for _, issue := range r.Issues {
+		if issue.Orphaned || len(issue.Repaired) < len(issue.Missing)+len(issue.Divergent) {
+			return false
+		}
+	}
+	return true
+}
+
+// VerifyConsistency enumerates the words in every backend and compares their
+// copies by content hash against the source backend. Entries missing from a
+// backend or differing from the source are reported; missing cache entries are
+// expected and only reported when they differ. With repair set, the copy from
+// the source is written to every backend that lacks it or differs; dryRun
+// reports these repairs without writing. Entries absent from the source are
+// reported as orphaned and left alone.
+func (r *WordRepository) VerifyConsistency(source string, repair, dryRun bool) (*ConsistencyReport, error) {
+	sourceIndex := -1
+	for i, backend := range storeBackends {
+		if backend.name == source {
+			sourceIndex = i
+		}
+	}
+	if sourceIndex < 0 {
+		return nil, fmt.Errorf("unknown backend %q (available: %s)", source, strings.Join(BackendNames(), ", "))
+	}
+
+	report := &ConsistencyReport{Source: source, Repair: repair, DryRun: dryRun, Issues: []ConsistencyIssue{}}
+
+	// Only hashes are kept in memory; source words are read again for repairs
+	hashes := make([]map[string]string, len(storeBackends))
+	words := make(map[string]string) // entry ID to display word
+	for i, backend := range storeBackends {
+		summary := BackendConsistency{Backend: backend.name, Cache: backend.cache}
+		hashes[i] = make(map[string]string)
+		err := backend.forEach(func(word *models.Word) error {
+			hash, err := models.ContentHash(word)
+			if err != nil {
+				return err
+			}
+			key := entryKey(word)
+			hashes[i][key] = hash
+			words[key] = word.Word
+			return nil
+		})
+		summary.Documents = len(hashes[i])
+		if err != nil {
+			logger.Error("Failed to read words for consistency check",
+				logger.F("backend", backend.name), logger.F("error", err))
+			summary.Error = err.Error()
+			hashes[i] = nil
+		}
+		report.Backends = append(report.Backends, summary)
+	}
+	if hashes[sourceIndex] == nil {
+		return report, fmt.Errorf("source backend %s could not be read: %s", source, report.Backends[sourceIndex].Error)
+	}
+
+	keys := make([]string, 0, len(words))
+	for key := range words {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	report.Entries = len(keys)
+
+	// targets maps entries to repair to the backends that need the source copy
+	targets := make(map[string][]int)
+	for _, key := range keys {
+		issue := ConsistencyIssue{ID: key, Word: words[key], Hashes: make(map[string]string)}
+		sourceHash, inSource := hashes[sourceIndex][key]
+		issue.Orphaned = !inSource
+
+		for i, backend := range storeBackends {
+			if hashes[i] == nil {
+				continue
+			}
+			hash, exists := hashes[i][key]
+			switch {
+			case exists:
+				issue.Hashes[backend.name] = hash
+				if inSource && hash != sourceHash {
+					issue.Divergent = append(issue.Divergent, backend.name)
+					report.Backends[i].Divergent++
+					targets[key] = append(targets[key], i)
+				}
+			case !backend.cache:
+				issue.Missing = append(issue.Missing, backend.name)
+				report.Backends[i].Missing++
+				if inSource {
+					targets[key] = append(targets[key], i)
+				}
+			}
+		}
+
+		if len(issue.Missing) > 0 || len(issue.Divergent) > 0 {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	if !repair || len(targets) == 0 {
+		return report, nil
+	}
+
+	issues := make(map[string]*ConsistencyIssue, len(report.Issues))
+	for i := range report.Issues {
+		issues[report.Issues[i].ID] = &report.Issues[i]
+	}
+
+	err := storeBackends[sourceIndex].forEach(func(word *models.Word) error {
+		key := entryKey(word)
+		for _, i := range targets[key] {
+			backend := storeBackends[i]
+			issue := issues[key]
+			if !dryRun {
+				if err := backend.save(word); err != nil {
+					logger.Error("Failed to repair word",
+						logger.F("backend", backend.name), logger.F("word", word.Word), logger.F("error", err))
+					if issue.Errors == nil {
+						issue.Errors = make(map[string]string)
+					}
+					issue.Errors[backend.name] = err.Error()
+					continue
+				}
+			}
+			issue.Repaired = append(issue.Repaired, backend.name)
+			report.Backends[i].Repaired++
+		}
+		// Duplicates of an entry in the source are repaired once
+		delete(targets, key)
+		return nil
+	})
+
+	logger.Info("Consistency repair finished",
+		logger.F("source", source),
+		logger.F("issues", len(report.Issues)),
+		logger.F("dry_run", dryRun))
+	return report, err
+}
+
+// entryKey identifies an entry across backends; legacy documents without an ID use their word
+func entryKey(word *models.Word) string {
+	if word.ID != "" {
+		return word.ID
+	}
+	return word.Word
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/health"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+)
+
+// WithRequiredBackends sets the backends writes must reach and returns the
+// repository for chaining. Other backends are skipped with a warning while
+// they are unavailable.
+func (r *WordRepository) WithRequiredBackends(names []string) (*WordRepository, error) {
+	required := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isBackend(name) {
+			return r, fmt.Errorf("unknown backend %q (available: %s)", name, strings.Join(BackendNames(), ", "))
+		}
+		required[name] = true
+	}
+	r.required = required
+	return r, nil
+}
+
+// IsRequired reports whether the named backend is required
+func (r *WordRepository) IsRequired(name string) bool {
+	return r.required[name]
+}
+
+// isBackend reports whether name is one of the stores words are kept in
+func isBackend(name string) bool {
+	for _, backend := range storeBackends {
+		if backend.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Health connects to every backend and reports its state, latency and version
+func (r *WordRepository) Health() []health.Status {
+	statuses := make([]health.Status, 0, len(storeBackends))
+	for _, backend := range storeBackends {
+		status := backend.check()
+		status.Required = r.IsRequired(backend.name)
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// skipUnavailable returns nil, after a warning, when err only means that an
+// optional backend is unavailable
+func (r *WordRepository) skipUnavailable(backend string, err error) error {
+	if err == nil || r.IsRequired(backend) || !errors.Is(err, health.ErrUnavailable) {
+		return err
+	}
+	logger.Warn("Skipped unavailable database", logger.F("backend", backend), logger.F("error", err))
+	return nil
+}
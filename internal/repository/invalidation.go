@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// instanceID identifies this process in published invalidations
+var instanceID = newInstanceID()
+
+// newInstanceID returns a random identifier
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// invalidation holds the handlers of changed words
+type invalidation struct {
+	mutex     sync.RWMutex
+	handlers  []func(id, word string)
+	watchOnce sync.Once
+}
+
+// OnInvalidate registers fn to be called with the entry ID and display word of
+// every word saved by this process and, once WatchInvalidations runs, by
+// other instances sharing the Redis server
+func (r *WordRepository) OnInvalidate(fn func(id, word string)) {
+	r.invalidation.mutex.Lock()
+	defer r.invalidation.mutex.Unlock()
+	r.invalidation.handlers = append(r.invalidation.handlers, fn)
+}
+
+// WatchInvalidations subscribes to the words saved by other instances in the
+// background until ctx is done. Calling it again has no effect.
+func (r *WordRepository) WatchInvalidations(ctx context.Context) {
+	r.invalidation.watchOnce.Do(func() {
+		go redis.SubscribeInvalidations(ctx, func(message redis.Invalidation) {
+			if message.Origin == instanceID {
+				// Handled when saving
+				return
+			}
+			logger.Debug("Word changed by another instance", logger.F("id", message.ID), logger.F("origin", message.Origin))
+			r.notifyInvalidation(message.ID, message.Word)
+		})
+	})
+}
+
+// invalidate drops stale copies of a saved word: the Redis entry, unless it
+// was just rewritten, and the in-memory copies of every instance
+func (r *WordRepository) invalidate(word *models.Word, recached bool) {
+	if !recached {
+		// The outbox caches the new version later; until then readers fall through to MongoDB
+		if err := redis.DeleteCachedWord(word.ID); err != nil {
+			logger.Debug("Failed to invalidate cached word", logger.F("word", word.ID), logger.F("error", err))
+		}
+	}
+
+	r.announce(word)
+}
+
+// announce drops the in-memory copies of a word in this process and, through
+// Redis pub/sub, in every other instance
+func (r *WordRepository) announce(word *models.Word) {
+	r.notifyInvalidation(word.ID, word.Word)
+
+	err := redis.PublishInvalidation(redis.Invalidation{ID: word.ID, Word: word.Word, Origin: instanceID})
+	if err != nil {
+		logger.Debug("Failed to publish invalidation", logger.F("word", word.ID), logger.F("error", err))
+	}
+}
+
+// notifyInvalidation calls the registered handlers
+func (r *WordRepository) notifyInvalidation(id, word string) {
+	r.invalidation.mutex.RLock()
+	defer r.invalidation.mutex.RUnlock()
+	for _, fn := range r.invalidation.handlers {
+		fn(id, word)
+	}
+}
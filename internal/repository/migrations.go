@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/elasticsearch"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// MigrationResult summarizes a migration run against a single backend
+type MigrationResult struct {
+	Backend string
+	Scanned int
+	Updated int
+	Err     error
+}
+
+// StripSentinels removes legacy placeholder values ("n/a", "no_audio_available", ...)
+// from every stored word in all backends. With dryRun set, nothing is written back.
+func (r *WordRepository) StripSentinels(dryRun bool) []MigrationResult {
+	return r.migrate("Sentinel cleanup", dryRun, models.StripSentinels)
+}
+
+// AssignIdentity gives words stored before entries had IDs an entry ID and
+// lookup key, so they can be found case- and umlaut-insensitively
+func (r *WordRepository) AssignIdentity(dryRun bool) []MigrationResult {
+	return r.migrate("Identity assignment", dryRun, (*models.Word).EnsureIdentity)
+}
+
+// ConvertOrigin replaces the origin stored by older versions with an
+// etymology built by convert, in every stored word in all backends. Words that
+// already have an etymology only lose the origin.
+func (r *WordRepository) ConvertOrigin(dryRun bool, convert func(origin []models.EtymologySource) *models.Etymology) []MigrationResult {
+	return r.migrate("Etymology conversion", dryRun, func(word *models.Word) bool {
+		if len(word.Origin) == 0 {
+			return false
+		}
+		if word.Etymology == nil {
+			word.Etymology = convert(word.Origin)
+		}
+		word.Origin = nil
+		return true
+	})
+}
+
+// UpdateSearchMapping brings the mapping of the Elasticsearch index up to
+// date, rebuilding the index if a field changed its type. It returns one of
+// the elasticsearch.Mapping outcomes.
+func (r *WordRepository) UpdateSearchMapping(dryRun bool) (string, error) {
+	return elasticsearch.UpdateWordsMapping(dryRun)
+}
+
+// migrate applies transform to every stored word in all backends and writes back
+// the words it reports as changed
+func (r *WordRepository) migrate(name string, dryRun bool, transform func(word *models.Word) bool) []MigrationResult {
+	results := make([]MigrationResult, 0, len(storeBackends))
+	for _, m := range storeBackends {
+		result := MigrationResult{Backend: m.name}
+
+		// Collect first so that writes do not interfere with open cursors
+		var dirty []*models.Word
+		result.Err = m.forEach(func(word *models.Word) error {
+			result.Scanned++
+			if transform(word) {
+				dirty = append(dirty, word)
+			}
+			return nil
+		})
+
+		for _, word := range dirty {
+			if dryRun {
+				result.Updated++
+				continue
+			}
+			if err := m.save(word); err != nil {
+				logger.Error("Failed to write migrated word",
+					logger.F("backend", m.name),
+					logger.F("word", word.Word),
+					logger.F("error", err))
+				result.Err = err
+				continue
+			}
+			result.Updated++
+		}
+
+		logger.Info(name+" finished",
+			logger.F("backend", m.name),
+			logger.F("scanned", result.Scanned),
+			logger.F("updated", result.Updated),
+			logger.F("dry_run", dryRun))
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/elasticsearch"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/mongodb"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/postgres"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/amirhossein-jamali/goden-crawler/internal/outbox"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// WithOutbox routes saves through an outbox kept in store and returns the
+// repository for chaining. A nil store writes all databases synchronously.
+func (r *WordRepository) WithOutbox(store outbox.Store, options outbox.Options) *WordRepository {
+	if store == nil {
+		r.outbox = nil
+		return r
+	}
+
+	// MongoDB is the primary storage and is written while saving
+	r.outbox = outbox.NewDispatcher(store, []outbox.Backend{
+		{Name: "mongodb", Apply: mongodb.SaveWord},
+		{Name: "postgres", Apply: postgres.SaveWord},
+		{Name: "redis", Apply: func(word *models.Word) error {
+			if err := redis.CacheWord(word, r.CacheTTL); err != nil {
+				return err
+			}
+			// Drop copies read from the databases before the new version was written
+			r.announce(word)
+			return nil
+		}},
+		{Name: "elasticsearch", Apply: elasticsearch.IndexWord},
+	}, options)
+	return r
+}
+
+// Outbox returns the outbox dispatcher, or nil if writes are synchronous
+func (r *WordRepository) Outbox() *outbox.Dispatcher {
+	return r.outbox
+}
+
+// Flush waits up to timeout for the writes queued by this process to be
+// applied and reports whether the outbox is idle. Writes waiting for a retry
+// stay in the outbox.
+func (r *WordRepository) Flush(timeout time.Duration) bool {
+	if r.outbox == nil || !r.outbox.Active() {
+		return true
+	}
+	return r.outbox.Drain(timeout)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/mongodb"
+	"github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
+	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
+)
+
+// GetStoredWord reads a word from primary storage, bypassing the cache, so
+// that the copy is current and the read is not counted as an access
+func (r *WordRepository) GetStoredWord(id string) (*models.Word, error) {
+	return mongodb.GetWord(id)
+}
+
+// SaveWordVersion keeps a previous version of a word in primary storage
+func (r *WordRepository) SaveWordVersion(version *models.WordVersion) error {
+	return mongodb.SaveWordVersion(version)
+}
+
+// GetWordVersions returns the previous versions of an entry, newest first
+func (r *WordRepository) GetWordVersions(id string) ([]models.WordVersion, error) {
+	return mongodb.GetWordVersions(id)
+}
+
+// AccessCounts returns how often each of the given entries was read.
+// Entries that were never read are missing.
+func (r *WordRepository) AccessCounts(ids []string) (map[string]int64, error) {
+	return redis.AccessCounts(ids)
+}
+
+// RecordAccess counts a word as read by a user, e.g. to refresh popular
+// words first. Callers record the words they show, not the ones they probe.
+func (r *WordRepository) RecordAccess(word *models.Word) {
+	r.recordAccess(word)
+}
+
+// recordAccess counts reads of words. Counting is best effort and never fails a read.
+func (r *WordRepository) recordAccess(words ...*models.Word) {
+	ids := make([]string, 0, len(words))
+	for _, word := range words {
+		if word.ID != "" {
+			ids = append(ids, word.ID)
+		}
+	}
+	if err := redis.RecordAccess(ids...); err != nil {
+		logger.Debug("Failed to record word access", logger.F("words", len(ids)), logger.F("error", err))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/amirhossein-jamali/goden-crawler/internal/db/mongodb"
 	"github.com/amirhossein-jamali/goden-crawler/internal/db/postgres"
 	"github.com/amirhossein-jamali/goden-crawler/internal/db/redis"
+	"github.com/amirhossein-jamali/goden-crawler/internal/outbox"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/logger"
 	"github.com/amirhossein-jamali/goden-crawler/pkg/models"
 )
@@ -16,12 +17,22 @@ import (
 type WordRepository struct {
 	// Configuration options
 	CacheTTL time.Duration
+
+	// outbox records writes durably and replicates them in the background; nil writes synchronously
+	outbox *outbox.Dispatcher
+
+	// required are the backends writes must reach; others are skipped while unavailable
+	required map[string]bool
+
+	// invalidation notifies caches of saved words
+	invalidation invalidation
 }
 
 // NewWordRepository creates a new WordRepository
 func NewWordRepository() *WordRepository {
 	return &WordRepository{
 		CacheTTL: 24 * time.Hour, // Default cache TTL
+		required: map[string]bool{"mongodb": true},
 	}
 }
 
@@ -31,30 +42,58 @@ func (r *WordRepository) WithCacheTTL(ttl time.Duration) *WordRepository {
 	return r
 }
 
-// SaveWord saves a word to all databases
+// SaveWord saves a word to all databases, keyed by its entry ID. With an
+// outbox, only MongoDB is written synchronously and the other databases are
+// updated in the background. Cached copies of the word are invalidated.
 func (r *WordRepository) SaveWord(word *models.Word) error {
+	// Words without an ID (e.g. built by hand) fall back to the display word
+	word.EnsureIdentity()
+
+	if r.outbox != nil {
+		// Invalidate before the outbox workers can cache the new version, so
+		// that the fresh Redis entry is not deleted
+		r.invalidate(word, false)
+		err := r.outbox.Save(word)
+		if !errors.Is(err, outbox.ErrEnqueue) {
+			// The queued entry is retried once MongoDB is back
+			if err = r.skipUnavailable("mongodb", err); err != nil {
+				logger.Error("Failed to save word to MongoDB, queued for retry", logger.F("word", word.Word), logger.F("error", err))
+			}
+			return err
+		}
+		logger.Warn("Outbox unavailable, writing to all databases directly", logger.F("word", word.Word), logger.F("error", err))
+	}
+
+	err := r.saveDirect(word)
+	r.invalidate(word, true)
+	return err
+}
+
+// saveDirect writes a word to all databases synchronously, skipping optional
+// databases that are unavailable
+func (r *WordRepository) saveDirect(word *models.Word) error {
 	var lastErr error
 
 	// Save to MongoDB (primary storage)
-	if err := mongodb.SaveWord(word); err != nil {
+	if err := r.skipUnavailable("mongodb", mongodb.SaveWord(word)); err != nil {
 		logger.Error("Failed to save word to MongoDB", logger.F("word", word.Word), logger.F("error", err))
 		lastErr = err
 	}
 
 	// Save to PostgreSQL (relational data)
-	if err := postgres.SaveWord(word); err != nil {
+	if err := r.skipUnavailable("postgres", postgres.SaveWord(word)); err != nil {
 		logger.Error("Failed to save word to PostgreSQL", logger.F("word", word.Word), logger.F("error", err))
 		lastErr = err
 	}
 
 	// Cache in Redis
-	if err := redis.CacheWord(word, r.CacheTTL); err != nil {
+	if err := r.skipUnavailable("redis", redis.CacheWord(word, r.CacheTTL)); err != nil {
 		logger.Error("Failed to cache word in Redis", logger.F("word", word.Word), logger.F("error", err))
 		lastErr = err
 	}
 
 	// Index in Elasticsearch
-	if err := elasticsearch.IndexWord(word); err != nil {
+	if err := r.skipUnavailable("elasticsearch", elasticsearch.IndexWord(word)); err != nil {
 		logger.Error("Failed to index word in Elasticsearch", logger.F("word", word.Word), logger.F("error", err))
 		lastErr = err
 	}
@@ -62,7 +101,10 @@ func (r *WordRepository) SaveWord(word *models.Word) error {
 	return lastErr
 }
 
-// GetWord retrieves a word from the fastest available source
+// GetWord retrieves a word from the fastest available source; unavailable
+// databases are skipped. Reads are not counted as accesses, so that exports
+// and lookups of candidates do not make words popular; see RecordAccess.
+// wordText may be an entry ID or any spelling of the word; "haus" finds "Haus".
 func (r *WordRepository) GetWord(wordText string) (*models.Word, error) {
 	var word *models.Word
 	var err error
@@ -96,11 +138,39 @@ func (r *WordRepository) GetWord(wordText string) (*models.Word, error) {
 	return nil, errors.New("word not found in any database")
 }
 
+// GetCachedWords retrieves many words from the Redis cache in one batch,
+// keyed by the given text. Words that are not cached are missing; they can be
+// read with GetWord. Every word found is counted as an access.
+func (r *WordRepository) GetCachedWords(texts []string) map[string]*models.Word {
+	words, err := redis.GetCachedWords(texts)
+	if err != nil {
+		logger.Debug("Failed to read cached words", logger.F("words", len(texts)), logger.F("error", err))
+	}
+	if len(words) > 0 {
+		found := make([]*models.Word, 0, len(words))
+		for _, word := range words {
+			found = append(found, word)
+		}
+		r.recordAccess(found...)
+	}
+	return words
+}
+
+// ForEachWord streams every word in primary storage through fn
+func (r *WordRepository) ForEachWord(fn func(word *models.Word) error) error {
+	return mongodb.ForEachWord(fn)
+}
+
 // SearchWords searches for words in Elasticsearch
 func (r *WordRepository) SearchWords(query string) ([]models.Word, error) {
 	return elasticsearch.SearchWords(query)
 }
 
+// SearchByOriginLanguage finds words whose etymology mentions the given language
+func (r *WordRepository) SearchByOriginLanguage(language string) ([]models.Word, error) {
+	return elasticsearch.SearchByEtymologyLanguage(language, 100)
+}
+
 // Close closes all database connections
 func (r *WordRepository) Close() {
 	_ = mongodb.Close()
@@ -0,0 +1,117 @@
+// File: pkg/models/hash.go
+
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// ContentHash returns a SHA-256 checksum of the stored content of a word, so
+// that copies of an entry in different backends can be compared. Differences
+// the backends introduce on their own are ignored: MongoDB keeps timestamps
+// with millisecond precision in UTC, and empty lists may come back as null.
+// The Lookup field is not stored and not hashed.
+func ContentHash(word *Word) (string, error) {
+	normalized := *word
+	normalized.Lookup = nil
+	normalized.FetchedAt = storedTime(normalized.FetchedAt)
+	normalized.ChangedAt = storedTime(normalized.ChangedAt)
+	return hashJSON(&normalized)
+}
+
+// DictionaryHash returns a SHA-256 checksum of the dictionary content of a
+// word, i.e. what Duden publishes. Unlike ContentHash it leaves out the
+// identity of the entry (ID, URL and lookup key), when and how often it was
+// crawled and the locally downloaded media files, so two crawls of an
+// unchanged entry have the same hash even if it moved to another URL.
+func DictionaryHash(word *Word) (string, error) {
+	normalized := *word
+	normalized.ID = ""
+	normalized.SourceURL = ""
+	normalized.LookupKey = ""
+	normalized.Lookup = nil
+	normalized.FetchedAt = time.Time{}
+	normalized.ChangedAt = time.Time{}
+	normalized.Revision = 0
+	return hashJSON(&normalized, "audio_file", "image_file")
+}
+
+// storedTime rounds a time the way MongoDB stores it
+func storedTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC().Truncate(time.Millisecond)
+}
+
+// hashJSON hashes the canonical JSON encoding of value without the given
+// object keys at any depth
+func hashJSON(value interface{}, omit ...string) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	// Decoding into generic values sorts object keys when encoding again
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", err
+	}
+	for _, key := range omit {
+		deleteKey(decoded, key)
+	}
+	canonical, err := json.Marshal(pruneEmpty(decoded))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// pruneEmpty removes null values, empty strings, lists and objects from a decoded JSON value
+func pruneEmpty(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			if element = pruneEmpty(element); element == nil {
+				delete(v, key)
+			} else {
+				v[key] = element
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		for i, element := range v {
+			v[i] = pruneEmpty(element)
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return value
+}
+
+// deleteKey removes an object key from a decoded JSON value at any depth
+func deleteKey(value interface{}, key string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		delete(v, key)
+		for _, element := range v {
+			deleteKey(element, key)
+		}
+	case []interface{}:
+		for _, element := range v {
+			deleteKey(element, key)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// File: pkg/models/sentinels.go
+
+package models
+
+// Placeholder strings that earlier extractor versions stored in place of
+// missing data. They are kept here only so stored documents can be cleaned up.
+const (
+	sentinelNotAvailable      = "n/a"
+	sentinelNotAvailableUpper = "N/A"
+	sentinelNoAudio           = "no_audio_available"
+	sentinelNoDataAvailable   = "No data available"
+	sentinelNoData            = "No data"
+	sentinelNoLinks           = "No links available"
+	sentinelUnknown           = "unknown"
+)
+
+// isSentinel reports whether a value is one of the legacy placeholder strings
+func isSentinel(value string) bool {
+	switch value {
+	case sentinelNotAvailable, sentinelNotAvailableUpper, sentinelNoAudio,
+		sentinelNoDataAvailable, sentinelNoData, sentinelNoLinks:
+		return true
+	}
+	return false
+}
+
+// StripSentinels removes legacy placeholder values from a word in place.
+// It returns true if the word was modified.
+func StripSentinels(word *Word) bool {
+	if word == nil {
+		return false
+	}
+
+	changed := false
+	clearSentinel := func(value *string) {
+		if isSentinel(*value) {
+			*value = ""
+			changed = true
+		}
+	}
+
+	// Word type and frequency used "unknown" when the page had no data
+	var wordTypes []string
+	for _, wordType := range word.WordType {
+		if wordType == sentinelUnknown || isSentinel(wordType) {
+			changed = true
+			continue
+		}
+		wordTypes = append(wordTypes, wordType)
+	}
+	word.WordType = wordTypes
+
+	if word.Frequency == sentinelUnknown {
+		word.Frequency = ""
+		changed = true
+	}
+	clearSentinel(&word.Frequency)
+	clearSentinel(&word.Article)
+	clearSentinel(&word.Grammar)
+
+	if meanings, ok := stripMeaningSentinels(word.Meanings); ok {
+		word.Meanings = meanings
+		changed = true
+	}
+
+	var pronunciations []Pronunciation
+	for _, p := range word.Pronunciation {
+		clearSentinel(&p.Word)
+		clearSentinel(&p.Phonetic)
+		clearSentinel(&p.Audio)
+		if p.Word == "" && p.Phonetic == "" && p.Audio == "" {
+			changed = true
+			continue
+		}
+		pronunciations = append(pronunciations, p)
+	}
+	word.Pronunciation = pronunciations
+
+	if word.Spelling != nil {
+		clearSentinel(&word.Spelling.SyllabicDivision)
+		if word.Spelling.SyllabicDivision == "" && len(word.Spelling.Examples) == 0 && len(word.Spelling.Rules) == 0 {
+			word.Spelling = nil
+			changed = true
+		}
+	}
+
+	if word.Etymology != nil && isSentinel(word.Etymology.Text) {
+		word.Etymology = nil
+		changed = true
+	}
+
+	return changed
+}
+
+// stripMeaningSentinels cleans a meaning tree and drops meanings that only
+// consisted of placeholders. The second return value reports any change.
+func stripMeaningSentinels(meanings []Meaning) ([]Meaning, bool) {
+	changed := false
+	var result []Meaning
+
+	for _, m := range meanings {
+		for _, field := range []*string{&m.Text, &m.Grammar, &m.Image, &m.ImageCaption} {
+			if isSentinel(*field) {
+				*field = ""
+				changed = true
+			}
+		}
+
+		if subMeanings, ok := stripMeaningSentinels(m.SubMeanings); ok {
+			m.SubMeanings = subMeanings
+			changed = true
+		}
+
+		if m.Text == "" && m.Grammar == "" && m.Image == "" &&
+			len(m.Examples) == 0 && len(m.Idioms) == 0 && len(m.SubMeanings) == 0 && len(m.TupleInfo) == 0 {
+			changed = true
+			continue
+		}
+
+		result = append(result, m)
+	}
+
+	return result, changed
+}
@@ -0,0 +1,15 @@
+// File: pkg/models/version.go
+
+package models
+
+import "time"
+
+// WordVersion is a previous version of a stored entry, kept when crawling the
+// entry again found different content
+type WordVersion struct {
+	WordID     string    `json:"word_id"`
+	Revision   int       `json:"revision"`
+	Hash       string    `json:"hash"` // DictionaryHash of Word
+	ReplacedAt time.Time `json:"replaced_at"`
+	Word       *Word     `json:"word"`
+}
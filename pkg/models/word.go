@@ -1,44 +1,295 @@
 // ./pkg/models/word.go
 package models
 
-// Word represents a German word with its linguistic information
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/goden-crawler/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Word represents a German word with its linguistic information.
+// ID is the Duden URL slug and identifies the entry in every backend;
+// homonyms share Word and LookupKey but have distinct IDs.
+// Revision counts the content changes found when the entry was crawled again;
+// ChangedAt is the time of the last one.
 type Word struct {
-	Word          string          `json:"word"`
-	Article       string          `json:"article,omitempty"`
-	WordType      []string        `json:"word_type,omitempty"`
-	Frequency     string          `json:"frequency,omitempty"`
-	Grammar       string          `json:"grammar,omitempty"`
-	Meanings      []Meaning       `json:"meanings,omitempty"`
-	Synonyms      []Synonym       `json:"synonyms,omitempty"`
-	Pronunciation []Pronunciation `json:"pronunciation,omitempty"`
-	Spelling      Spelling        `json:"spelling,omitempty"`
-	Origin        []Origin        `json:"origin,omitempty"`
-	FunFacts      []string        `json:"fun_facts,omitempty"`
-}
-
-// Meaning represents a single meaning of a word
+	ID             string            `json:"id,omitempty"`
+	SourceURL      string            `json:"source_url,omitempty"`
+	FetchedAt      time.Time         `json:"fetched_at,omitzero"`
+	Revision       int               `json:"revision,omitempty"`
+	ChangedAt      time.Time         `json:"changed_at,omitzero"`
+	LookupKey      string            `json:"lookup_key,omitempty"`
+	Word           string            `json:"word"`
+	Article        string            `json:"article,omitempty"`
+	WordType       []string          `json:"word_type,omitempty"`
+	Frequency      string            `json:"frequency,omitempty"`
+	Grammar        string            `json:"grammar,omitempty"`
+	InflectedForms []string          `json:"inflected_forms,omitempty"`
+	Meanings       []Meaning         `json:"meanings,omitempty"`
+	Synonyms       []Synonym         `json:"synonyms,omitempty"`
+	SynonymGroups  []SynonymGroup    `json:"synonym_groups,omitempty"`
+	Antonyms       []RelatedWord     `json:"antonyms,omitempty"`
+	Collocations   []Collocation     `json:"collocations,omitempty"`
+	Compounds      []RelatedWord     `json:"compounds,omitempty"`
+	WordFamily     []RelatedWord     `json:"word_family,omitempty"`
+	Pronunciation  []Pronunciation   `json:"pronunciation,omitempty"`
+	Spelling       *Spelling         `json:"spelling,omitempty"`
+	Etymology      *Etymology        `json:"etymology,omitempty"`
+	Origin         []EtymologySource `json:"origin,omitempty" bson:",omitempty" xml:"-"` // legacy source words, see "migrate etymology"
+	FunFacts       []string          `json:"fun_facts,omitempty"`
+	Lookup         *Lookup           `json:"lookup,omitempty"`
+}
+
+// Lookup records how a user's input was resolved to this entry, e.g. the
+// inflected form "Häuser" to the lemma "Haus". It is set on results only
+// and not stored.
+type Lookup struct {
+	Form   string `json:"form"`
+	Lemma  string `json:"lemma"`
+	Method string `json:"method"`
+}
+
+// LookupKey normalizes user input for the case- and umlaut-insensitive index,
+// so that "haus", "Haus" and "HAUS" or "Tür" and "tuer" resolve to the same key
+func LookupKey(text string) string {
+	return utils.NormalizeGerman(text)
+}
+
+// EnsureIdentity fills in the ID and lookup key of words stored before entries
+// had IDs. The display word is used as ID. It reports whether anything changed.
+func (w *Word) EnsureIdentity() bool {
+	changed := false
+	if w.ID == "" && w.Word != "" {
+		w.ID = w.Word
+		changed = true
+	}
+	if key := LookupKey(w.Word); w.LookupKey != key {
+		w.LookupKey = key
+		changed = true
+	}
+	return changed
+}
+
+// pluralPattern finds the plural in the grammar summary, e.g. "Plural: die Häuser"
+var pluralPattern = regexp.MustCompile(`Plural:\s*([^;,]+)`)
+
+// Plural returns the plural given in the grammar summary such as
+// "das Haus; Genitiv: des Hauses, Plural: die Häuser", or "" if there is none
+func (w *Word) Plural() string {
+	if match := pluralPattern.FindStringSubmatch(w.Grammar); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// Meaning represents a single meaning of a word.
+// Meanings form a tree: SubMeanings hold the lettered senses (1a, 1b) of a numbered meaning.
 type Meaning struct {
+	Number       string            `json:"number,omitempty"`
 	Text         string            `json:"text"`
 	Grammar      string            `json:"grammar,omitempty"`
-	Examples     []string          `json:"examples,omitempty"`
-	Idioms       []string          `json:"idioms,omitempty"`
+	Examples     []Example         `json:"examples,omitempty"`
+	Idioms       []Idiom           `json:"idioms,omitempty"`
 	Image        string            `json:"image,omitempty"`
 	ImageCaption string            `json:"image_caption,omitempty"`
+	ImageFile    *MediaFile        `json:"image_file,omitempty"`
 	SubMeanings  []Meaning         `json:"sub_meanings,omitempty"`
-	TupleInfo    map[string]string `json:"tuple_info,omitempty"`
+	TupleInfo    map[string]string `json:"tuple_info,omitempty" xml:"-"` // maps cannot be encoded as XML
+	Labels
+}
+
+// SubMeaningNumber numbers the index-th sub-meaning of the meaning numbered
+// parent the way Duden does: letters below numbered meanings ("1a") and greek
+// letters below those ("1aα"). depth is the level of the sub-meaning, 1 for
+// the sub-meanings of a top-level meaning. Past the last letter numbering
+// continues with two letters ("1aa", "1ab").
+func SubMeaningNumber(parent string, index, depth int) string {
+	if depth == 1 {
+		return parent + letterNumber(latinLetters, index)
+	}
+	return parent + letterNumber(greekLetters, index)
+}
+
+// Letters used to number sub-meanings. The Greek alphabet leaves out the
+// final sigma "ς", which lies between "ρ" and "σ" in Unicode.
+var (
+	latinLetters = []rune("abcdefghijklmnopqrstuvwxyz")
+	greekLetters = []rune("αβγδεζηθικλμνξοπρστυφχψω")
+)
+
+// letterNumber returns the index-th number of the sequence a, b, …, z, aa, ab, …
+// spelled with letters
+func letterNumber(letters []rune, index int) string {
+	var number []rune
+	for index >= 0 {
+		number = append([]rune{letters[index%len(letters)]}, number...)
+		index = index/len(letters) - 1
+	}
+	return string(number)
+}
+
+// Labels holds Duden's usage annotations such as "(gehoben)", "(Kaufmannssprache)"
+// or "〈in übertragener Bedeutung:〉"
+type Labels struct {
+	Register   []string `json:"register,omitempty"`
+	Domain     []string `json:"domain,omitempty"`
+	Figurative bool     `json:"figurative,omitempty"`
+}
+
+// HasRegister reports whether any of the given register labels is present
+func (l Labels) HasRegister(registers ...string) bool {
+	for _, have := range l.Register {
+		for _, want := range registers {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsEmpty reports whether no label is set
+func (l Labels) IsEmpty() bool {
+	return len(l.Register) == 0 && len(l.Domain) == 0 && !l.Figurative
+}
+
+// Example represents a usage example. Inline explanations such as the
+// "(nebeneinander)" in "Haus an Haus (nebeneinander) wohnen" are moved to Gloss.
+type Example struct {
+	Text  string `json:"text"`
+	Gloss string `json:"gloss,omitempty"`
+	Labels
+}
+
+// UnmarshalJSON accepts both the structured form and plain strings
+// stored by earlier versions
+func (e *Example) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*e = Example{Text: text}
+		return nil
+	}
+
+	type plain Example
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// UnmarshalBSONValue accepts both the structured form and plain strings
+// stored in MongoDB by earlier versions
+func (e *Example) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	type plain Example
+	return unmarshalLegacyString(t, data, (*plain)(e), func(text string) { *e = Example{Text: text} })
 }
 
-// Synonym represents a synonym with optional link
+// Idiom represents an idiom, saying or proverb with its explanation
+type Idiom struct {
+	Text        string `json:"text"`
+	Explanation string `json:"explanation,omitempty"`
+	Labels
+}
+
+// UnmarshalJSON accepts both the structured form and plain strings
+// stored by earlier versions
+func (i *Idiom) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*i = Idiom{Text: text}
+		return nil
+	}
+
+	type plain Idiom
+	return json.Unmarshal(data, (*plain)(i))
+}
+
+// UnmarshalBSONValue accepts both the structured form and plain strings
+// stored in MongoDB by earlier versions
+func (i *Idiom) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	type plain Idiom
+	return unmarshalLegacyString(t, data, (*plain)(i), func(text string) { *i = Idiom{Text: text} })
+}
+
+// unmarshalLegacyString decodes a BSON value into target, or passes it to
+// setText if it is a plain string. target must not implement
+// UnmarshalBSONValue itself.
+func unmarshalLegacyString(t bsontype.Type, data []byte, target interface{}, setText func(text string)) error {
+	value := bson.RawValue{Type: t, Value: data}
+	if t == bsontype.String {
+		text, ok := value.StringValueOK()
+		if !ok {
+			return fmt.Errorf("invalid BSON string")
+		}
+		setText(text)
+		return nil
+	}
+	return value.Unmarshal(target)
+}
+
+// ExampleTexts returns the plain text of all examples of the meaning
+func (m Meaning) ExampleTexts() []string {
+	texts := make([]string, 0, len(m.Examples))
+	for _, example := range m.Examples {
+		texts = append(texts, example.Text)
+	}
+	return texts
+}
+
+// Entry identifies one dictionary entry for a lemma. Homonyms such as "Bank"
+// have several entries, each with its own Duden URL slug (Bank_Sitzgelegenheit).
+type Entry struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title,omitempty"`
+	Link  string `json:"link"`
+}
+
+// Synonym represents a synonym with optional link and usage labels
 type Synonym struct {
 	Text string `json:"text"`
 	Link string `json:"link,omitempty"`
+	Labels
+}
+
+// SynonymGroup holds the synonyms Duden lists for one sense of a word
+type SynonymGroup struct {
+	Sense    string    `json:"sense,omitempty"`
+	Synonyms []Synonym `json:"synonyms"`
+}
+
+// RelatedWord is a word an entry links to, such as an antonym ("Gegenwort"),
+// a compound or a derived member of its word family
+type RelatedWord struct {
+	Text string `json:"text"`
+	Link string `json:"link,omitempty"`
+}
+
+// Collocation is a typical word combination ("Typische Verbindungen") with its
+// relative strength between 0 and 1; the strongest combination has strength 1
+type Collocation struct {
+	Text     string  `json:"text"`
+	Link     string  `json:"link,omitempty"`
+	Strength float64 `json:"strength"`
 }
 
 // Pronunciation represents pronunciation information
 type Pronunciation struct {
-	Word     string `json:"word"`
-	Phonetic string `json:"phonetic"`
-	Audio    string `json:"audio,omitempty"`
+	Word      string     `json:"word"`
+	Phonetic  string     `json:"phonetic,omitempty"`
+	Audio     string     `json:"audio,omitempty"`
+	AudioFile *MediaFile `json:"audio_file,omitempty"`
+}
+
+// MediaFile is a downloaded copy of a remote asset in the content-addressed
+// media store. Path is relative to the store root.
+type MediaFile struct {
+	Checksum    string `json:"checksum"`
+	Size        int64  `json:"size"`
+	Path        string `json:"path"`
+	ContentType string `json:"content_type,omitempty"`
 }
 
 // Spelling represents spelling information
@@ -54,8 +305,31 @@ type Rule struct {
 	Link string `json:"link"`
 }
 
-// Origin represents word origin information
-type Origin struct {
-	Word string `json:"word"`
-	Link string `json:"link,omitempty"`
+// Etymology represents the origin ("Herkunft") of a word as readable text
+// together with the source words it mentions
+type Etymology struct {
+	Text    string            `json:"text"`
+	Sources []EtymologySource `json:"sources,omitempty"`
+}
+
+// EtymologySource is a word form an entry derives from, e.g. althochdeutsch "hūs"
+type EtymologySource struct {
+	Word     string `json:"word"`
+	Language string `json:"language,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
+// HasLanguage reports whether any source word comes from the given language.
+// Qualified tags match their base language, so "französisch" matches "altfranzösisch".
+func (e *Etymology) HasLanguage(language string) bool {
+	if e == nil {
+		return false
+	}
+	language = strings.ToLower(language)
+	for _, source := range e.Sources {
+		if strings.HasSuffix(strings.ToLower(source.Language), language) {
+			return true
+		}
+	}
+	return false
 }
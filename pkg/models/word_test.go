@@ -0,0 +1,90 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestDecodeLegacyBSON decodes a document written before examples and idioms
+// were structured, when both were stored as arrays of strings.
+func TestDecodeLegacyBSON(t *testing.T) {
+	legacy, err := bson.Marshal(bson.M{
+		"word": "Haus",
+		"meanings": bson.A{
+			bson.M{
+				"text":     "Gebäude, das Menschen zum Wohnen dient",
+				"examples": bson.A{"ein großes Haus", "ein Haus bauen"},
+				"idioms":   bson.A{"Haus und Hof"},
+				"submeanings": bson.A{
+					bson.M{"text": "Wohnung", "examples": bson.A{"außer Haus essen"}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var word Word
+	if err := bson.Unmarshal(legacy, &word); err != nil {
+		t.Fatalf("decoding legacy document: %v", err)
+	}
+
+	meaning := word.Meanings[0]
+	if want := []Example{{Text: "ein großes Haus"}, {Text: "ein Haus bauen"}}; !reflect.DeepEqual(meaning.Examples, want) {
+		t.Errorf("examples = %+v, want %+v", meaning.Examples, want)
+	}
+	if want := []Idiom{{Text: "Haus und Hof"}}; !reflect.DeepEqual(meaning.Idioms, want) {
+		t.Errorf("idioms = %+v, want %+v", meaning.Idioms, want)
+	}
+	if want := []Example{{Text: "außer Haus essen"}}; !reflect.DeepEqual(meaning.SubMeanings[0].Examples, want) {
+		t.Errorf("sub-meaning examples = %+v, want %+v", meaning.SubMeanings[0].Examples, want)
+	}
+}
+
+// TestBSONRoundTrip makes sure structured examples and idioms still decode.
+func TestBSONRoundTrip(t *testing.T) {
+	original := Word{
+		Word: "Haus",
+		Meanings: []Meaning{{
+			Text:     "Gebäude",
+			Examples: []Example{{Text: "Haus an Haus wohnen", Gloss: "nebeneinander", Labels: Labels{Register: []string{"umgangssprachlich"}}}},
+			Idioms:   []Idiom{{Text: "das Haus hüten", Explanation: "zu Hause bleiben"}},
+		}},
+	}
+	data, err := bson.Marshal(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Word
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Meanings, original.Meanings) {
+		t.Errorf("meanings = %+v, want %+v", decoded.Meanings, original.Meanings)
+	}
+}
+
+func TestSubMeaningNumber(t *testing.T) {
+	tests := []struct {
+		index, depth int
+		want         string
+	}{
+		{0, 1, "1a"},
+		{25, 1, "1z"},
+		{26, 1, "1aa"},
+		{27, 1, "1ab"},
+		{0, 2, "1α"},
+		{16, 2, "1ρ"},
+		{17, 2, "1σ"},
+		{23, 2, "1ω"},
+		{24, 2, "1αα"},
+	}
+	for _, tt := range tests {
+		if got := SubMeaningNumber("1", tt.index, tt.depth); got != tt.want {
+			t.Errorf("SubMeaningNumber(1, %d, %d) = %q, want %q", tt.index, tt.depth, got, tt.want)
+		}
+	}
+}
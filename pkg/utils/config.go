@@ -5,6 +5,7 @@ package utils
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,6 +23,38 @@ type Config struct {
 	// Logging settings
 	LogLevel        string
 	EnableColorLogs bool
+
+	// Media settings
+	MediaDir      string
+	DownloadMedia bool
+
+	// Plugin settings
+	PluginDir string
+
+	// Template settings
+	TemplateDir string
+
+	// Outbox settings
+	OutboxStore        string // "mongodb", "file" or "off"
+	OutboxDir          string
+	OutboxWorkers      int
+	OutboxMaxAttempts  int
+	OutboxDrainTimeout time.Duration
+
+	// Database settings
+	RequiredBackends []string // backends that must be available; others are skipped while down
+
+	// Work queue settings
+	QueueVisibility   time.Duration // lease of a word before another worker may take it over
+	QueueMaxAttempts  int
+	QueueRateInterval time.Duration // interval between Duden requests of all workers together
+	QueueRateJitter   time.Duration
+	QueueJobRetention time.Duration // time the progress of a finished job is kept
+
+	// Refresh settings
+	RefreshMaxAge   time.Duration // stored words older than this are crawled again
+	RefreshInterval time.Duration // pause between runs of the refresh daemon
+	RefreshPriority string        // "frequency" or "access"
 }
 
 // DefaultConfig returns the default configuration
@@ -34,6 +67,28 @@ func DefaultConfig() *Config {
 		DudenSearchURL:  "https://www.duden.de/suchen/dudenonline/",
 		LogLevel:        "INFO",
 		EnableColorLogs: true,
+		MediaDir:        "media",
+		DownloadMedia:   false,
+		PluginDir:       "",
+		TemplateDir:     "",
+
+		OutboxStore:        "mongodb",
+		OutboxDir:          "outbox",
+		OutboxWorkers:      4,
+		OutboxMaxAttempts:  8,
+		OutboxDrainTimeout: 30 * time.Second,
+
+		RequiredBackends: []string{"mongodb"},
+
+		QueueVisibility:   2 * time.Minute,
+		QueueMaxAttempts:  5,
+		QueueRateInterval: time.Second,
+		QueueRateJitter:   500 * time.Millisecond,
+		QueueJobRetention: 7 * 24 * time.Hour,
+
+		RefreshMaxAge:   30 * 24 * time.Hour,
+		RefreshInterval: time.Hour,
+		RefreshPriority: "frequency",
 	}
 }
 
@@ -72,6 +127,85 @@ func LoadConfig() *Config {
 		config.EnableColorLogs = colorLogs
 	}
 
+	// Load media settings
+	if mediaDir := getEnv("MEDIA_DIR", ""); mediaDir != "" {
+		config.MediaDir = mediaDir
+	}
+
+	if downloadMedia, err := strconv.ParseBool(getEnv("DOWNLOAD_MEDIA", "false")); err == nil {
+		config.DownloadMedia = downloadMedia
+	}
+
+	// Load plugin settings
+	if pluginDir := getEnv("PLUGIN_DIR", ""); pluginDir != "" {
+		config.PluginDir = pluginDir
+	}
+
+	// Load template settings
+	if templateDir := getEnv("TEMPLATE_DIR", ""); templateDir != "" {
+		config.TemplateDir = templateDir
+	}
+
+	// Load outbox settings
+	if outboxStore := getEnv("OUTBOX", ""); outboxStore != "" {
+		config.OutboxStore = outboxStore
+	}
+
+	if outboxDir := getEnv("OUTBOX_DIR", ""); outboxDir != "" {
+		config.OutboxDir = outboxDir
+	}
+
+	if workers, err := strconv.Atoi(getEnv("OUTBOX_WORKERS", "4")); err == nil {
+		config.OutboxWorkers = workers
+	}
+
+	if attempts, err := strconv.Atoi(getEnv("OUTBOX_MAX_ATTEMPTS", "8")); err == nil {
+		config.OutboxMaxAttempts = attempts
+	}
+
+	if drain, err := strconv.Atoi(getEnv("OUTBOX_DRAIN_SECONDS", "30")); err == nil {
+		config.OutboxDrainTimeout = time.Duration(drain) * time.Second
+	}
+
+	// Load database settings
+	if required, exists := os.LookupEnv("REQUIRED_BACKENDS"); exists {
+		config.RequiredBackends = strings.Split(required, ",")
+	}
+
+	// Load work queue settings
+	if visibility, err := strconv.Atoi(getEnv("QUEUE_VISIBILITY_SECONDS", "120")); err == nil {
+		config.QueueVisibility = time.Duration(visibility) * time.Second
+	}
+
+	if attempts, err := strconv.Atoi(getEnv("QUEUE_MAX_ATTEMPTS", "5")); err == nil {
+		config.QueueMaxAttempts = attempts
+	}
+
+	if interval, err := strconv.Atoi(getEnv("QUEUE_RATE_MS", "1000")); err == nil {
+		config.QueueRateInterval = time.Duration(interval) * time.Millisecond
+	}
+
+	if jitter, err := strconv.Atoi(getEnv("QUEUE_RATE_JITTER_MS", "500")); err == nil {
+		config.QueueRateJitter = time.Duration(jitter) * time.Millisecond
+	}
+
+	if retention, err := strconv.Atoi(getEnv("QUEUE_JOB_RETENTION_HOURS", "168")); err == nil {
+		config.QueueJobRetention = time.Duration(retention) * time.Hour
+	}
+
+	// Load refresh settings
+	if maxAge, err := strconv.Atoi(getEnv("REFRESH_MAX_AGE_HOURS", "720")); err == nil {
+		config.RefreshMaxAge = time.Duration(maxAge) * time.Hour
+	}
+
+	if interval, err := strconv.Atoi(getEnv("REFRESH_INTERVAL_MINUTES", "60")); err == nil {
+		config.RefreshInterval = time.Duration(interval) * time.Minute
+	}
+
+	if priority := getEnv("REFRESH_PRIORITY", ""); priority != "" {
+		config.RefreshPriority = priority
+	}
+
 	return config
 }
 
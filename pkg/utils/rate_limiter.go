@@ -0,0 +1,50 @@
+// File: pkg/utils/rate_limiter.go
+
+package utils
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Limiter spaces out requests; Wait blocks until the next request may be made
+type Limiter interface {
+	Wait()
+}
+
+// RateLimiter spaces out requests so that callers sharing it never exceed one
+// request per interval. A random jitter is added to avoid a regular pattern.
+type RateLimiter struct {
+	interval time.Duration
+	jitter   time.Duration
+	next     time.Time
+	mutex    sync.Mutex
+}
+
+// NewRateLimiter creates a RateLimiter allowing one request per interval plus up to jitter
+func NewRateLimiter(interval, jitter time.Duration) *RateLimiter {
+	return &RateLimiter{
+		interval: interval,
+		jitter:   jitter,
+	}
+}
+
+// Wait blocks until the caller may make its next request
+func (r *RateLimiter) Wait() {
+	r.mutex.Lock()
+	now := time.Now()
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+
+	delay := r.interval
+	if r.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(r.jitter)))
+	}
+	r.next = slot.Add(delay)
+	r.mutex.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
@@ -21,6 +21,30 @@ func CleanText(text string) string {
 	return strings.TrimSpace(text)
 }
 
+// germanTransliterations maps umlauts and ß to the ASCII spelling Duden uses in URLs
+var germanTransliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"Ä", "ae", "Ö", "oe", "Ü", "ue", "ẞ", "ss",
+)
+
+// NormalizeGerman lowercases a word and transliterates umlauts and ß,
+// so that "Tür", "tür" and "Tuer" all compare equal
+func NormalizeGerman(s string) string {
+	return strings.ToLower(germanTransliterations.Replace(strings.TrimSpace(s)))
+}
+
+// dudenSlugTransliterations keep the case of umlauts, as Duden does in entry URLs
+var dudenSlugTransliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"Ä", "Ae", "Ö", "Oe", "Ü", "Ue", "ẞ", "SS",
+	" ", "_",
+)
+
+// ToDudenSlug converts a lemma to the slug of its Duden entry URL, e.g. "schön" -> "schoen"
+func ToDudenSlug(s string) string {
+	return dudenSlugTransliterations.Replace(strings.TrimSpace(s))
+}
+
 // SplitAndTrim splits a string by a separator and trims each part
 func SplitAndTrim(s, sep string) []string {
 	if s == "" {